@@ -0,0 +1,121 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/dimuls/mipt-hack-accenture/capacity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+type resourceGroupLoad struct {
+	ResourceGroupID string              `json:"resource_group_id"`
+	Bucket          capacity.BucketSize `json:"bucket"`
+	Buckets         []capacity.Bucket   `json:"buckets"`
+}
+
+func (s *Server) getResourceGroupLoad(c echo.Context) error {
+	from, err := s.queryTime(c, "from")
+	if err != nil {
+		return err
+	}
+
+	to, err := s.queryTime(c, "to")
+	if err != nil {
+		return err
+	}
+
+	size := capacity.Day
+	if b := c.QueryParam("bucket"); b != "" {
+		size, err = capacity.ParseBucketSize(b)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	rg, err := s.storage.ResourceGroups.Get(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound,
+				"resource group not found")
+		}
+		return fmt.Errorf("get resource group: %w", err)
+	}
+
+	bs, err := capacity.Buckets(from, to, size)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// Buckets are aligned, so they may cover more than requested.
+	ops, err := s.storage.SupplyOrderOperations.ByResourceGroup(rg.ID,
+		bs[0].Start, bs[len(bs)-1].End)
+	if err != nil {
+		return fmt.Errorf("list resource group operations: %w", err)
+	}
+
+	capacity.Load(bs, rg.Periods, ops)
+
+	return c.JSON(http.StatusOK, resourceGroupLoad{
+		ResourceGroupID: rg.ID,
+		Bucket:          size,
+		Buckets:         bs,
+	})
+}
+
+func (s *Server) getBottlenecks(c echo.Context) error {
+	from, err := s.queryTime(c, "from")
+	if err != nil {
+		return err
+	}
+
+	to, err := s.queryTime(c, "to")
+	if err != nil {
+		return err
+	}
+
+	weeks, err := capacity.Buckets(from, to, capacity.Week)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	rgs, err := s.storage.ResourceGroups.ListWithPeriods()
+	if err != nil {
+		return fmt.Errorf("list resource groups: %w", err)
+	}
+
+	var groups []capacity.Group
+
+	for _, rg := range rgs {
+		ops, err := s.storage.SupplyOrderOperations.ByResourceGroup(rg.ID,
+			weeks[0].Start, weeks[len(weeks)-1].End)
+		if err != nil {
+			return fmt.Errorf("list resource group operations: %w", err)
+		}
+
+		groups = append(groups, capacity.Group{
+			ResourceGroup: rg,
+			Operations:    ops,
+		})
+	}
+
+	sos, err := s.storage.SupplyOrders.List()
+	if err != nil {
+		return fmt.Errorf("list supply orders: %w", err)
+	}
+
+	colIDs := map[string]string{}
+	for _, so := range sos {
+		colIDs[so.ID] = so.ColID
+	}
+
+	a, err := capacity.Bottlenecks(groups, from, to, colIDs)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, a)
+}
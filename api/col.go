@@ -0,0 +1,64 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+
+	"github.com/dimuls/mipt-hack-accenture/pegging"
+	"github.com/dimuls/mipt-hack-accenture/requirement"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+func (s *Server) getColRequirement(c echo.Context) error {
+	tolerance := requirement.DefaultTolerance
+
+	var err error
+
+	if t := c.QueryParam("tolerance"); t != "" {
+		tolerance, err = strconv.ParseFloat(t, 64)
+		if err != nil || tolerance < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest,
+				"tolerance must be non-negative number")
+		}
+	}
+
+	col, err := s.storage.Cols.Get(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "COL not found")
+		}
+		return fmt.Errorf("get COL: %w", err)
+	}
+
+	req, err := requirement.Load(s.storage, col, tolerance)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusConflict,
+				"routing of COL not found")
+		}
+		return fmt.Errorf("load COL requirement: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, req)
+}
+
+func (s *Server) getColPegging(c echo.Context) error {
+	col, err := s.storage.Cols.Get(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "COL not found")
+		}
+		return fmt.Errorf("get COL: %w", err)
+	}
+
+	p, err := pegging.Load(s.storage, col)
+	if err != nil {
+		return fmt.Errorf("load COL pegging: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, p)
+}
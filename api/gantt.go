@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/dimuls/mipt-hack-accenture/gantt"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+func (s *Server) getGantt(c echo.Context) error {
+	from, err := s.queryTime(c, "from")
+	if err != nil {
+		return err
+	}
+
+	to, err := s.queryTime(c, "to")
+	if err != nil {
+		return err
+	}
+
+	if !from.Before(to) {
+		return echo.NewHTTPError(http.StatusBadRequest,
+			"from must be before to")
+	}
+
+	format := c.QueryParam("format")
+	if format != "" && format != "json" && format != "svg" {
+		return echo.NewHTTPError(http.StatusBadRequest,
+			"format must be json or svg")
+	}
+
+	id := c.QueryParam("resource_group_id")
+	if id != "" {
+		_, err = s.storage.ResourceGroups.Get(id)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound,
+					"resource group not found")
+			}
+			return fmt.Errorf("get resource group: %w", err)
+		}
+	}
+
+	chart, err := gantt.Load(s.storage, id, from, to)
+	if err != nil {
+		return fmt.Errorf("load gantt chart: %w", err)
+	}
+
+	if format == "svg" {
+		c.Response().Header().Set(echo.HeaderContentType, "image/svg+xml")
+		c.Response().WriteHeader(http.StatusOK)
+		return gantt.WriteSVG(c.Response(), chart)
+	}
+
+	return c.JSON(http.StatusOK, chart)
+}
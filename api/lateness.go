@@ -0,0 +1,46 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/dimuls/mipt-hack-accenture/duration"
+	"github.com/dimuls/mipt-hack-accenture/lateness"
+)
+
+func (s *Server) getLateness(c echo.Context) error {
+	margin := lateness.DefaultMargin
+
+	if m := c.QueryParam("margin"); m != "" {
+		var err error
+		margin, err = duration.ParseISO(m)
+		if err != nil {
+			margin, err = duration.Parse(m)
+		}
+		if err != nil || margin < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest,
+				"margin must be non-negative ISO 8601 or clock duration")
+		}
+	}
+
+	r, err := lateness.Load(s.storage, margin)
+	if err != nil {
+		return fmt.Errorf("load lateness: %w", err)
+	}
+
+	switch c.QueryParam("format") {
+	case "", "json":
+		return c.JSON(http.StatusOK, r)
+	case "csv":
+		c.Response().Header().Set(echo.HeaderContentType, "text/csv")
+		c.Response().Header().Set(echo.HeaderContentDisposition,
+			`attachment; filename="lateness.csv"`)
+		c.Response().WriteHeader(http.StatusOK)
+		return lateness.WriteCSV(c.Response(), r)
+	}
+
+	return echo.NewHTTPError(http.StatusBadRequest,
+		"format must be json or csv")
+}
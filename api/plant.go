@@ -0,0 +1,52 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+func (s *Server) getPlants(c echo.Context) error {
+	ps, err := s.storage.Plants.List()
+	if err != nil {
+		return fmt.Errorf("list plants: %w", err)
+	}
+
+	if ps == nil {
+		ps = []entity.Plant{}
+	}
+
+	return c.JSON(http.StatusOK, ps)
+}
+
+// plantView is plant with its resource groups and their resources.
+type plantView struct {
+	entity.Plant
+	ResourceGroups []entity.ResourceGroup `json:"resource_groups"`
+}
+
+func (s *Server) getPlant(c echo.Context) error {
+	p, err := s.storage.Plants.Get(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "plant not found")
+		}
+		return fmt.Errorf("get plant: %w", err)
+	}
+
+	rgs, err := s.storage.ResourceGroups.ByPlant(p.ID)
+	if err != nil {
+		return fmt.Errorf("list plant resource groups: %w", err)
+	}
+
+	if rgs == nil {
+		rgs = []entity.ResourceGroup{}
+	}
+
+	return c.JSON(http.StatusOK, plantView{Plant: p, ResourceGroups: rgs})
+}
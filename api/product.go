@@ -0,0 +1,65 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/dimuls/mipt-hack-accenture/routing"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+func (s *Server) getProductUpstream(c echo.Context) error {
+	return s.explodeProduct(c, (*routing.Graph).Upstream)
+}
+
+func (s *Server) getProductDownstream(c echo.Context) error {
+	return s.explodeProduct(c, (*routing.Graph).Downstream)
+}
+
+func (s *Server) explodeProduct(c echo.Context,
+	explode func(*routing.Graph, string) (routing.Node, error)) error {
+
+	p, err := s.storage.Products.Get(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "product not found")
+		}
+		return fmt.Errorf("get product: %w", err)
+	}
+
+	g, err := routing.Load(s.storage)
+	if err != nil {
+		return fmt.Errorf("load routing graph: %w", err)
+	}
+
+	n, err := explode(g, p.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, routing.ErrCycle):
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		case errors.Is(err, routing.ErrTooLarge):
+			return echo.NewHTTPError(http.StatusUnprocessableEntity,
+				err.Error())
+		}
+		return fmt.Errorf("explode product: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, n)
+}
+
+func (s *Server) getRoutingCycles(c echo.Context) error {
+	g, err := routing.Load(s.storage)
+	if err != nil {
+		return fmt.Errorf("load routing graph: %w", err)
+	}
+
+	cycles := g.Cycles()
+	if cycles == nil {
+		cycles = [][]string{}
+	}
+
+	return c.JSON(http.StatusOK, cycles)
+}
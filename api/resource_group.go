@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+func (s *Server) getResourceGroups(c echo.Context) error {
+	rgs, err := s.storage.ResourceGroups.List()
+	if err != nil {
+		return fmt.Errorf("list resource groups: %w", err)
+	}
+
+	if rgs == nil {
+		rgs = []entity.ResourceGroup{}
+	}
+
+	return c.JSON(http.StatusOK, rgs)
+}
+
+func (s *Server) getResourceGroup(c echo.Context) error {
+	rg, err := s.storage.ResourceGroups.Get(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound,
+				"resource group not found")
+		}
+		return fmt.Errorf("get resource group: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, rg)
+}
+
+func (s *Server) createResourceGroup(c echo.Context) error {
+	var rg entity.ResourceGroup
+
+	err := c.Bind(&rg)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if rg.ID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "id is required")
+	}
+
+	setResourceGroupChildrenID(&rg)
+
+	err = s.storage.ResourceGroups.Add(rg)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrAlreadyExists):
+			return echo.NewHTTPError(http.StatusConflict,
+				"resource group or its child already exists")
+		case errors.Is(err, storage.ErrBrokenReference):
+			return echo.NewHTTPError(http.StatusBadRequest,
+				"plant not found")
+		}
+		return fmt.Errorf("add resource group: %w", err)
+	}
+
+	return c.JSON(http.StatusCreated, rg)
+}
+
+func (s *Server) updateResourceGroup(c echo.Context) error {
+	var rg entity.ResourceGroup
+
+	err := c.Bind(&rg)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	rg.ID = c.Param("id")
+
+	setResourceGroupChildrenID(&rg)
+
+	err = s.storage.ResourceGroups.Update(rg)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			return echo.NewHTTPError(http.StatusNotFound,
+				"resource group not found")
+		case errors.Is(err, storage.ErrAlreadyExists):
+			return echo.NewHTTPError(http.StatusConflict,
+				"resource group child already exists")
+		case errors.Is(err, storage.ErrBrokenReference):
+			return echo.NewHTTPError(http.StatusBadRequest,
+				"plant not found")
+		}
+		return fmt.Errorf("update resource group: %w", err)
+	}
+
+	return c.JSON(http.StatusOK, rg)
+}
+
+func (s *Server) deleteResourceGroup(c echo.Context) error {
+	err := s.storage.ResourceGroups.Remove(c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			return echo.NewHTTPError(http.StatusNotFound,
+				"resource group not found")
+		case errors.Is(err, storage.ErrBrokenReference):
+			return echo.NewHTTPError(http.StatusConflict,
+				"resource group is used by routing steps or operations")
+		}
+		return fmt.Errorf("remove resource group: %w", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func setResourceGroupChildrenID(rg *entity.ResourceGroup) {
+	for i := range rg.Resources {
+		rg.Resources[i].ResourceGroupID = rg.ID
+	}
+	for i := range rg.Periods {
+		rg.Periods[i].ResourceGroupID = rg.ID
+	}
+}
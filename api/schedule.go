@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo"
+
+	"github.com/dimuls/mipt-hack-accenture/scheduler"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+type scheduleRequest struct {
+	// SupplyOrderIDs are orders to schedule, all orders if empty.
+	SupplyOrderIDs []string `json:"supply_order_ids"`
+	// Start is earliest start, now if not set.
+	Start time.Time `json:"start"`
+	// Mode is forward or backward, forward by default.
+	Mode string `json:"mode"`
+	// DryRun disables saving of schedule.
+	DryRun bool `json:"dry_run"`
+}
+
+func (s *Server) schedule(c echo.Context) error {
+	var req scheduleRequest
+
+	err := c.Bind(&req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	algorithm, err := scheduler.ParseAlgorithm(req.Mode)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if req.Start.IsZero() {
+		req.Start = time.Now().In(s.location)
+	}
+
+	in, err := scheduler.LoadInput(s.storage, req.SupplyOrderIDs, req.Start)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound,
+				"supply order not found")
+		}
+		return fmt.Errorf("load schedule input: %w", err)
+	}
+
+	sch := algorithm(in, req.Start)
+
+	if !req.DryRun {
+		err = s.storage.SupplyOrderOperations.SetTimes(sch.Operations)
+		if err != nil {
+			return fmt.Errorf("save schedule: %w", err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, sch)
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/labstack/echo/middleware"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+type Server struct {
+	storage storage.Storage
+	// location is time zone of dates in queries.
+	location *time.Location
+	echo     *echo.Echo
+}
+
+func NewServer(st storage.Storage, loc *time.Location) *Server {
+	s := &Server{storage: st, location: loc}
+
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.HTTPErrorHandler = s.handleError
+
+	e.Use(middleware.Recover())
+	e.Use(middleware.CORS())
+
+	e.GET("/plant", s.getPlants)
+	e.GET("/plant/:id", s.getPlant)
+
+	e.GET("/resource-group", s.getResourceGroups)
+	e.POST("/resource-group", s.createResourceGroup)
+	e.GET("/resource-group/:id", s.getResourceGroup)
+	e.PUT("/resource-group/:id", s.updateResourceGroup)
+	e.DELETE("/resource-group/:id", s.deleteResourceGroup)
+	e.GET("/resource-group/:id/load", s.getResourceGroupLoad)
+
+	e.GET("/col/:id/requirement", s.getColRequirement)
+	e.GET("/col/:id/pegging", s.getColPegging)
+
+	e.GET("/product/:id/upstream", s.getProductUpstream)
+	e.GET("/product/:id/downstream", s.getProductDownstream)
+	e.GET("/routing/cycles", s.getRoutingCycles)
+
+	e.GET("/bottleneck", s.getBottlenecks)
+
+	e.GET("/lateness", s.getLateness)
+
+	e.GET("/gantt", s.getGantt)
+
+	e.POST("/schedule", s.schedule)
+
+	s.echo = e
+
+	return s
+}
+
+// Start starts serving HTTP on addr and blocks until server is shut down.
+func (s *Server) Start(addr string) error {
+	err := s.echo.Start(addr)
+	if err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// Shutdown gracefully stops server waiting for active requests to finish.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.echo.Shutdown(ctx)
+}
+
+// queryTime parses query param as date in server time zone or as RFC 3339
+// time.
+func (s *Server) queryTime(c echo.Context, name string) (time.Time, error) {
+	v := c.QueryParam(name)
+	if v == "" {
+		return time.Time{}, echo.NewHTTPError(http.StatusBadRequest,
+			name+" is required")
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", v, s.location)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, echo.NewHTTPError(http.StatusBadRequest,
+			name+" must be date or RFC 3339 time")
+	}
+
+	return t.In(s.location), nil
+}
+
+func (s *Server) handleError(err error, c echo.Context) {
+	if _, ok := err.(*echo.HTTPError); !ok {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"method": c.Request().Method,
+			"path":   c.Request().URL.Path,
+		}).Error("failed to handle request")
+	}
+	s.echo.DefaultHTTPErrorHandler(err, c)
+}
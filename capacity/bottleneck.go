@@ -0,0 +1,188 @@
+package capacity
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+// Group is resource group with its periods and operations overlapping
+// analysed horizon.
+type Group struct {
+	entity.ResourceGroup
+	Operations []entity.SupplyOrderOperation
+}
+
+// Rank is load of resource group over whole horizon.
+type Rank struct {
+	ResourceGroupID string          `json:"resource_group_id"`
+	Name            string          `json:"name"`
+	Available       entity.Duration `json:"available"`
+	Planned         entity.Duration `json:"planned"`
+	// Queued is planned time which exceeds available one.
+	Queued      entity.Duration `json:"queued"`
+	Utilization *float64        `json:"utilization"`
+}
+
+// Bottleneck is the most utilized resource group of week.
+type Bottleneck struct {
+	Start           time.Time       `json:"start"`
+	End             time.Time       `json:"end"`
+	ResourceGroupID string          `json:"resource_group_id"`
+	Name            string          `json:"name"`
+	Available       entity.Duration `json:"available"`
+	Planned         entity.Duration `json:"planned"`
+	Utilization     *float64        `json:"utilization"`
+	Overloaded      bool            `json:"overloaded"`
+	// ColIDs are COLs which operations go through bottleneck within week.
+	ColIDs []string `json:"col_ids"`
+}
+
+// Analysis is result of bottleneck detection.
+type Analysis struct {
+	Ranking     []Rank       `json:"ranking"`
+	Bottlenecks []Bottleneck `json:"bottlenecks"`
+}
+
+// Bottlenecks ranks resource groups with finite capacity by utilization and
+// queued work within [from, to) and finds bottleneck of every week. Weeks
+// are as returned by Buckets. Map colIDs gives COL of supply order.
+func Bottlenecks(groups []Group, from, to time.Time,
+	colIDs map[string]string) (Analysis, error) {
+
+	weeks, err := Buckets(from, to, Week)
+	if err != nil {
+		return Analysis{}, err
+	}
+
+	a := Analysis{
+		Ranking:     []Rank{},
+		Bottlenecks: []Bottleneck{},
+	}
+
+	type groupWeeks struct {
+		group Group
+		weeks []Bucket
+	}
+
+	var finite []groupWeeks
+
+	for _, g := range groups {
+		horizon := []Bucket{{Start: from, End: to}}
+		Load(horizon, g.Periods, g.Operations)
+
+		h := horizon[0]
+		if !h.Finite {
+			continue
+		}
+
+		r := Rank{
+			ResourceGroupID: g.ID,
+			Name:            g.Name,
+			Available:       h.Available,
+			Planned:         h.Planned,
+			Utilization:     h.Utilization,
+		}
+		if h.Free < 0 {
+			r.Queued = -h.Free
+		}
+
+		a.Ranking = append(a.Ranking, r)
+
+		gw := make([]Bucket, len(weeks))
+		copy(gw, weeks)
+		Load(gw, g.Periods, g.Operations)
+
+		finite = append(finite, groupWeeks{group: g, weeks: gw})
+	}
+
+	sort.SliceStable(a.Ranking, func(i, j int) bool {
+		ui := utilization(a.Ranking[i].Planned, a.Ranking[i].Utilization)
+		uj := utilization(a.Ranking[j].Planned, a.Ranking[j].Utilization)
+		if ui != uj {
+			return ui > uj
+		}
+		if a.Ranking[i].Queued != a.Ranking[j].Queued {
+			return a.Ranking[i].Queued > a.Ranking[j].Queued
+		}
+		return a.Ranking[i].ResourceGroupID < a.Ranking[j].ResourceGroupID
+	})
+
+	for wi, w := range weeks {
+		best := -1
+		bestU := 0.
+
+		for gi, gw := range finite {
+			b := gw.weeks[wi]
+			if b.Planned <= 0 {
+				continue
+			}
+			u := utilization(b.Planned, b.Utilization)
+			if best < 0 || u > bestU {
+				best, bestU = gi, u
+			}
+		}
+
+		if best < 0 {
+			continue
+		}
+
+		g := finite[best].group
+		b := finite[best].weeks[wi]
+
+		a.Bottlenecks = append(a.Bottlenecks, Bottleneck{
+			Start:           w.Start,
+			End:             w.End,
+			ResourceGroupID: g.ID,
+			Name:            g.Name,
+			Available:       b.Available,
+			Planned:         b.Planned,
+			Utilization:     b.Utilization,
+			Overloaded:      b.Overloaded,
+			ColIDs:          weekCols(g.Operations, w, colIDs),
+		})
+	}
+
+	return a, nil
+}
+
+// utilization returns utilization for ranking, work without available time
+// is ranked first.
+func utilization(planned entity.Duration, u *float64) float64 {
+	if u != nil {
+		return *u
+	}
+	if planned > 0 {
+		return math.Inf(1)
+	}
+	return 0
+}
+
+// weekCols returns sorted COLs of operations which are planned within week.
+func weekCols(ops []entity.SupplyOrderOperation, w Bucket,
+	colIDs map[string]string) []string {
+
+	seen := map[string]bool{}
+	cols := []string{}
+
+	for _, op := range ops {
+		if share(op.ProductTime, op.StartTime, op.EndTime, w.Start,
+			w.End) <= 0 {
+			continue
+		}
+
+		colID, exists := colIDs[op.SupplyOrderID]
+		if !exists || seen[colID] {
+			continue
+		}
+
+		seen[colID] = true
+		cols = append(cols, colID)
+	}
+
+	sort.Strings(cols)
+
+	return cols
+}
@@ -0,0 +1,181 @@
+// Package capacity compares available capacity of resource groups with
+// operations planned on them.
+package capacity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+// BucketSize is length of report bucket.
+type BucketSize string
+
+const (
+	Day   BucketSize = "day"
+	Week  BucketSize = "week"
+	Month BucketSize = "month"
+)
+
+// MaxBuckets limits number of buckets of single report.
+const MaxBuckets = 1000
+
+// ParseBucketSize returns bucket size by its name.
+func ParseBucketSize(s string) (BucketSize, error) {
+	switch BucketSize(s) {
+	case Day, Week, Month:
+		return BucketSize(s), nil
+	}
+	return "", fmt.Errorf("unknown bucket %q, day, week or month expected", s)
+}
+
+// Bucket is load of resource group within [Start, End).
+type Bucket struct {
+	Start     time.Time       `json:"start"`
+	End       time.Time       `json:"end"`
+	Available entity.Duration `json:"available"`
+	Planned   entity.Duration `json:"planned"`
+	Free      entity.Duration `json:"free"`
+	// Utilization is planned time in percents of available one, it is nil
+	// if there is no available time.
+	Utilization *float64 `json:"utilization"`
+	// Finite is true if any period of bucket has finite capacity.
+	Finite bool `json:"finite"`
+	// Overloaded is true if planned time exceeds finite capacity.
+	Overloaded bool `json:"overloaded"`
+}
+
+// Buckets returns empty buckets covering [from, to). Buckets are aligned to
+// start of day, ISO week or month in location of from.
+func Buckets(from, to time.Time, size BucketSize) ([]Bucket, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+
+	var bs []Bucket
+
+	for start := truncate(from, size); start.Before(to); {
+		end := next(start, size)
+
+		bs = append(bs, Bucket{Start: start, End: end})
+
+		if len(bs) > MaxBuckets {
+			return nil, fmt.Errorf("more than %d buckets", MaxBuckets)
+		}
+
+		start = end
+	}
+
+	return bs, nil
+}
+
+func truncate(t time.Time, size BucketSize) time.Time {
+	y, m, d := t.Date()
+
+	switch size {
+	case Week:
+		// ISO week starts on Monday.
+		d -= (int(t.Weekday()) + 6) % 7
+	case Month:
+		d = 1
+	}
+
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+func next(t time.Time, size BucketSize) time.Time {
+	switch size {
+	case Week:
+		return t.AddDate(0, 0, 7)
+	case Month:
+		return t.AddDate(0, 1, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// Load fills buckets with load of resource group.
+//
+// Period lasts until start of next period, last period lasts as long as
+// previous one or day if it is the only one. Its capacity is spread evenly
+// over its time. Production time of operation is spread evenly between its
+// start and end.
+func Load(bs []Bucket, periods []entity.ResourceGroupPeriod,
+	ops []entity.SupplyOrderOperation) {
+
+	for i, p := range periods {
+		end := PeriodEnd(periods, i)
+
+		for bi := range bs {
+			b := &bs[bi]
+			if overlap(p.StartDate, end, b.Start, b.End) > 0 {
+				b.Finite = b.Finite || p.HasFinateCapacity
+			}
+			b.Available += share(p.AvailableCapacity, p.StartDate, end,
+				b.Start, b.End)
+		}
+	}
+
+	for _, op := range ops {
+		for bi := range bs {
+			b := &bs[bi]
+			b.Planned += share(op.ProductTime, op.StartTime, op.EndTime,
+				b.Start, b.End)
+		}
+	}
+
+	for bi := range bs {
+		b := &bs[bi]
+
+		b.Free = b.Available - b.Planned
+
+		if b.Available > 0 {
+			u := float64(b.Planned) / float64(b.Available) * 100
+			b.Utilization = &u
+		}
+
+		b.Overloaded = b.Finite && b.Planned > b.Available
+	}
+}
+
+// PeriodEnd returns end of i-th period of periods sorted by start date.
+func PeriodEnd(periods []entity.ResourceGroupPeriod, i int) time.Time {
+	switch {
+	case i+1 < len(periods):
+		return periods[i+1].StartDate
+	case i > 0:
+		return periods[i].StartDate.Add(
+			periods[i].StartDate.Sub(periods[i-1].StartDate))
+	}
+	return periods[i].StartDate.AddDate(0, 0, 1)
+}
+
+// share returns part of d spread over [start, end) which falls into
+// [from, to). Instant is wholly in interval containing it.
+func share(d entity.Duration, start, end, from, to time.Time) entity.Duration {
+	if !end.After(start) {
+		if !start.Before(from) && start.Before(to) {
+			return d
+		}
+		return 0
+	}
+
+	o := overlap(start, end, from, to)
+	if o <= 0 {
+		return 0
+	}
+
+	return entity.Duration(float64(d) * float64(o) / float64(end.Sub(start)))
+}
+
+// overlap returns length of intersection of [s1, e1) and [s2, e2).
+func overlap(s1, e1, s2, e2 time.Time) time.Duration {
+	s, e := s1, e1
+	if s2.After(s) {
+		s = s2
+	}
+	if e2.Before(e) {
+		e = e2
+	}
+	return e.Sub(s)
+}
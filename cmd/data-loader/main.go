@@ -4,908 +4,563 @@ import (
 	"encoding/csv"
 	"flag"
 	"fmt"
-	"io"
-	"math"
 	"os"
 	"path"
-	"regexp"
 	"strconv"
 	"strings"
-	"time"
-
-	"github.com/lib/pq"
+	"text/tabwriter"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+
+	"github.com/dimuls/mipt-hack-accenture/dataset"
+	"github.com/dimuls/mipt-hack-accenture/postgres"
+)
+
+const (
+	modeInsert = "insert"
+	modeUpsert = "upsert"
+	modeSync   = "sync"
 )
 
 func main() {
 	logrus.SetLevel(logrus.DebugLevel)
 
 	var (
-		dataPath string
-		dbURI    string
-		table    string
+		dataPath       string
+		dbURI          string
+		tableName      string
+		batchSize      int
+		mode           string
+		atomic         bool
+		truncate       bool
+		skipMigrations bool
+		dryRun         bool
+		maxErrors      int
+		rejectsPath    string
+		skipOrphans    bool
+		timezone       string
+		timeLayouts    layoutsFlag
 	)
 
 	flag.StringVar(&dataPath, "d", "", "data path")
 	flag.StringVar(&dbURI, "u", "", "db uri")
-	flag.StringVar(&table, "t", "", "table to load")
+	flag.StringVar(&tableName, "t", "", "table to load")
+	flag.IntVar(&batchSize, "b", postgres.DefaultBatchSize,
+		"rows per COPY batch")
+	flag.StringVar(&mode, "mode", modeInsert,
+		"load mode: insert, upsert or sync (upsert and delete missing rows, "+
+			"requires -atomic)")
+	flag.BoolVar(&atomic, "atomic", false,
+		"load all tables in one transaction")
+	flag.BoolVar(&truncate, "truncate", false,
+		"truncate loaded tables before load, tables referencing them "+
+			"must be loaded too")
+	flag.BoolVar(&skipMigrations, "skip-migrations", false,
+		"don't apply migrations before load")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"validate data files and print report without touching DB")
+	flag.IntVar(&maxErrors, "max-errors", 0,
+		"malformed lines skipped before load is aborted, -1 for unlimited")
+	flag.StringVar(&rejectsPath, "rejects", ".",
+		"path to write per table files with rejected lines")
+	flag.BoolVar(&skipOrphans, "skip-orphan-check", false,
+		"don't check references to missing rows before load")
+	flag.StringVar(&timezone, "tz", "UTC",
+		"time zone of timestamps, for example Europe/Moscow")
+	flag.Var(&timeLayouts, "time-layout",
+		"timestamp layouts of column as table.column=layout[|layout...], "+
+			"may be repeated")
 
 	flag.Parse()
 
-	db, err := sqlx.Connect("postgres", dbURI)
-	if err != nil {
-		logrus.WithError(err).Fatal("failed to connect to DB")
+	switch mode {
+	case modeInsert, modeUpsert, modeSync:
+	default:
+		logrus.Fatal("unknown mode")
 	}
 
-	defer func() {
-		err := db.Close()
-		if err != nil {
-			logrus.WithError(err).Fatal("failed to close DB")
-		}
-	}()
-
-	switch table {
-	case "":
-		err = loadPlant(dataPath, db)
-		if err != nil {
-			break
-		}
-
-		logrus.Info("plant loaded")
-
-		err = loadStockingPoint(dataPath, db)
-		if err != nil {
-			break
-		}
-
-		logrus.Info("stocking_point loaded")
-
-		err = loadResourceGroup(dataPath, db)
-		if err != nil {
-			break
-		}
-
-		logrus.Info("resource_group loaded")
-
-		err = loadResource(dataPath, db)
-		if err != nil {
-			break
-		}
-
-		logrus.Info("resource loaded")
-
-		err = loadProduct(dataPath, db)
-		if err != nil {
-			break
-		}
-
-		logrus.Info("product loaded")
-
-		err = loadResourceGroupPeriod(dataPath, db)
-		if err != nil {
-			break
-		}
-
-		logrus.Info("resource_group_period loaded")
+	// Sync deletes rows which may be referenced by tables loaded later, so
+	// foreign keys can be checked only on commit of all tables.
+	if mode == modeSync && !atomic {
+		logrus.Fatal("sync mode requires -atomic")
+	}
 
-		err = loadRouting(dataPath, db)
-		if err != nil {
-			break
-		}
+	dc, err := dataset.NewConfig(timezone, timeLayouts)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to configure dataset")
+	}
 
-		logrus.Info("routing loaded")
+	var toLoad []dataset.Table
 
-		err = loadRoutingStep(dataPath, db)
-		if err != nil {
-			break
+	for _, t := range dataset.Tables {
+		if tableName == "" || tableName == t.Name {
+			toLoad = append(toLoad, t)
 		}
+	}
 
-		logrus.Info("routing_step loaded")
-
-		err = loadCol(dataPath, db)
-		if err != nil {
-			break
-		}
+	if len(toLoad) == 0 {
+		logrus.Fatal("unknown table")
+	}
 
-		logrus.Info("col loaded")
+	if dryRun {
+		ok := validate(dataPath, dc, toLoad)
 
-		err = loadSupplyOrder(dataPath, db)
+		// References to tables which are not loaded are checked against
+		// their data files.
+		orphans, err := dataset.FindOrphans(dataPath, dc, toLoad,
+			func(table string, _ []string) (map[string]bool, error) {
+				if loaded(toLoad, table) {
+					return nil, nil
+				}
+				t, _ := dataset.TableByName(table)
+				return t.Keys(dataPath, dc)
+			})
 		if err != nil {
-			break
+			logrus.WithError(err).Fatal("failed to check references")
 		}
 
-		logrus.Info("supply_order loaded")
+		printOrphans(orphans)
 
-		err = loadSupplyOrderOperation(dataPath, db)
-		if err != nil {
-			break
+		if !ok || len(orphans) > 0 {
+			os.Exit(1)
 		}
-
-		logrus.Info("supply_order_operation loaded")
-
-	case "plant":
-		err = loadPlant(dataPath, db)
-	case "stocking_point":
-		err = loadStockingPoint(dataPath, db)
-	case "resource_group":
-		err = loadResourceGroup(dataPath, db)
-	case "resource":
-		err = loadResource(dataPath, db)
-	case "product":
-		err = loadProduct(dataPath, db)
-	case "resource_group_period":
-		err = loadResourceGroupPeriod(dataPath, db)
-	case "routing":
-		err = loadRouting(dataPath, db)
-	case "routing_step":
-		err = loadRoutingStep(dataPath, db)
-	case "col":
-		err = loadCol(dataPath, db)
-	case "supply_order":
-		err = loadSupplyOrder(dataPath, db)
-	case "supply_order_operation":
-		err = loadSupplyOrderOperation(dataPath, db)
-	default:
-		logrus.Fatal("unknown table")
-	}
-
-	if err != nil {
-		logrus.WithError(err).Fatal("failed to load data")
+		return
 	}
-}
 
-func loadPlant(dataPath string, db *sqlx.DB) error {
-	f, err := os.Open(path.Join(dataPath, "plant.csv"))
+	db, err := sqlx.Connect("postgres", dbURI)
 	if err != nil {
-		return fmt.Errorf("failed to open data file: %w", err)
+		logrus.WithError(err).Fatal("failed to connect to DB")
 	}
 
 	defer func() {
-		err := f.Close()
+		err := db.Close()
 		if err != nil {
-			logrus.WithError(err).Error("failed to close data file")
+			logrus.WithError(err).Fatal("failed to close DB")
 		}
 	}()
 
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = 3
-
-	// skip header
-	_, err = r.Read()
-	if err != nil {
-		return fmt.Errorf("failed to skip header: %w", err)
-	}
-
-	for {
-		l, err := r.Read()
+	if !skipMigrations {
+		m, err := postgres.NewMigrator(db)
 		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return fmt.Errorf("failed to read line: %w", err)
+			logrus.WithError(err).Fatal("failed to create migrator")
 		}
 
-		_, err = db.Exec(`
-			insert into plant (id, name, description) values ($1, $2, $3)
-		`, l[0], l[1], l[2])
+		err = m.Up()
 		if err != nil {
-			return fmt.Errorf("failed to insert line to DB: %w", err)
+			logrus.WithError(err).Fatal("failed to apply migrations")
 		}
 	}
 
-	return nil
-}
-
-func loadStockingPoint(dataPath string, db *sqlx.DB) error {
-	f, err := os.Open(path.Join(dataPath, "stocking-point.csv"))
-	if err != nil {
-		return fmt.Errorf("failed to open data file: %w", err)
-	}
-
-	defer func() {
-		err := f.Close()
+	if truncate {
+		err = checkTruncate(db, toLoad)
 		if err != nil {
-			logrus.WithError(err).Error("failed to close data file")
+			logrus.WithError(err).Fatal("can't truncate tables")
 		}
-	}()
-
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = 2
+	}
 
-	// skip header
-	_, err = r.Read()
-	if err != nil {
-		return fmt.Errorf("failed to skip header: %w", err)
+	l := &loader{
+		dataPath:    dataPath,
+		config:      dc,
+		db:          db,
+		batchSize:   batchSize,
+		mode:        mode,
+		maxErrors:   maxErrors,
+		rejectsPath: rejectsPath,
+		rejected:    map[string]int{},
 	}
 
-	for {
-		l, err := r.Read()
+	if !skipOrphans {
+		// Rows kept in DB satisfy references unless they are replaced.
+		orphans, err := dataset.FindOrphans(dataPath, dc, toLoad,
+			func(table string, keys []string) (map[string]bool, error) {
+				if loaded(toLoad, table) && (truncate || mode == modeSync) {
+					return nil, nil
+				}
+				return tableKeys(db, table, keys)
+			})
 		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return fmt.Errorf("failed to read line: %w", err)
+			logrus.WithError(err).Fatal("failed to check references")
 		}
 
-		_, err = db.Exec(`
-			insert into stocking_point (id, name) values ($1, $2)
-		`, l[0], l[1])
-		if err != nil {
-			return fmt.Errorf("failed to insert line to DB: %w", err)
+		if len(orphans) > 0 {
+			printOrphans(orphans)
+			logrus.Fatal("orphan references found, nothing is loaded")
 		}
 	}
 
-	return nil
-}
-
-func loadResourceGroup(dataPath string, db *sqlx.DB) error {
-	f, err := os.Open(path.Join(dataPath, "resource-group.csv"))
-	if err != nil {
-		return fmt.Errorf("failed to open data file: %w", err)
-	}
-
-	defer func() {
-		err := f.Close()
+	if atomic {
+		l.tx, err = db.Beginx()
 		if err != nil {
-			logrus.WithError(err).Error("failed to close data file")
+			logrus.WithError(err).Fatal("failed to begin transaction")
 		}
-	}()
-
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = 5
 
-	// skip header
-	_, err = r.Read()
-	if err != nil {
-		return fmt.Errorf("failed to skip header: %w", err)
+		// Foreign keys are checked on commit, so tables can be loaded and
+		// synced in any order.
+		_, err = l.tx.Exec(`set constraints all deferred`)
+		if err != nil {
+			logrus.WithError(err).Fatal("failed to defer constraints")
+		}
 	}
 
-	resourceGroups := map[string]string{}
-
-	for {
-		l, err := r.Read()
-		if err != nil {
-			if err == io.EOF {
-				break
+	stats, err := l.run(toLoad, truncate)
+	if err != nil {
+		if l.tx != nil {
+			rbErr := l.tx.Rollback()
+			if rbErr != nil {
+				logrus.WithError(rbErr).Error(
+					"failed to rollback transaction")
+			} else {
+				logrus.Warn("transaction rolled back, DB is left untouched")
 			}
-			return fmt.Errorf("failed to read line: %w", err)
+		} else {
+			// Report already loaded tables since they are kept.
+			report(toLoad, stats, l.rejected)
 		}
-		resourceGroups[l[0]] = l[1]
+		logrus.WithError(err).Fatal("failed to load data")
 	}
 
-	for id, name := range resourceGroups {
-		_, err = db.Exec(`
-			insert into resource_group (id, name) values ($1, $2)
-		`, id, name)
+	if l.tx != nil {
+		err = l.tx.Commit()
 		if err != nil {
-			return fmt.Errorf("failed to insert line to DB: %w", err)
+			logrus.WithError(err).Fatal("failed to commit transaction")
 		}
 	}
 
-	return nil
-}
+	report(toLoad, stats, l.rejected)
 
-func loadResource(dataPath string, db *sqlx.DB) error {
-	f, err := os.Open(path.Join(dataPath, "resource-group.csv"))
-	if err != nil {
-		return fmt.Errorf("failed to open data file: %w", err)
+	// Exit code is 1 if load failed or error budget is exceeded and 2 if
+	// data is loaded with rejected lines within budget.
+	if l.errors > 0 {
+		logrus.WithField("rejected", l.errors).Warn(
+			"data loaded with rejected lines")
+		os.Exit(2)
 	}
+}
 
-	defer func() {
-		err := f.Close()
-		if err != nil {
-			logrus.WithError(err).Error("failed to close data file")
-		}
-	}()
-
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = 5
+// layoutsFlag collects timestamp layouts by table.column.
+type layoutsFlag map[string][]string
 
-	// skip header
-	_, err = r.Read()
-	if err != nil {
-		return fmt.Errorf("failed to skip header: %w", err)
+func (f *layoutsFlag) String() string {
+	var s []string
+	for column, layouts := range *f {
+		s = append(s, column+"="+strings.Join(layouts, "|"))
 	}
+	return strings.Join(s, ", ")
+}
 
-	for {
-		l, err := r.Read()
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return fmt.Errorf("failed to read line: %w", err)
-		}
-
-		_, err = db.Exec(`
-			insert into resource (id, resource_group_id, short_name, long_name)
-			values ($1, $2, $3, $4)
-		`, l[2], l[0], l[3], l[4])
-		if err != nil {
-			return fmt.Errorf("failed to insert line to DB: %w", err)
-		}
+func (f *layoutsFlag) Set(v string) error {
+	parts := strings.SplitN(v, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("%s is not in table.column=layout form", v)
 	}
-
+	if *f == nil {
+		*f = layoutsFlag{}
+	}
+	(*f)[parts[0]] = strings.Split(parts[1], "|")
 	return nil
 }
 
-func loadProduct(dataPath string, db *sqlx.DB) error {
-	f, err := os.Open(path.Join(dataPath, "product.csv"))
-	if err != nil {
-		return fmt.Errorf("failed to open data file: %w", err)
-	}
+type loader struct {
+	dataPath  string
+	config    dataset.Config
+	db        *sqlx.DB
+	tx        *sqlx.Tx // not nil in atomic mode
+	batchSize int
+	mode      string
+
+	maxErrors   int
+	rejectsPath string
+	errors      int            // total number of rejected lines
+	rejected    map[string]int // number of rejected lines by table
+}
 
-	defer func() {
-		err := f.Close()
-		if err != nil {
-			logrus.WithError(err).Error("failed to close data file")
+func (l *loader) run(toLoad []dataset.Table,
+	truncate bool) (map[string]postgres.MergeStats, error) {
+
+	if truncate {
+		var names []string
+		for _, t := range toLoad {
+			names = append(names, t.Name)
 		}
-	}()
 
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = 2
+		q := "truncate table " + strings.Join(names, ", ")
 
-	// skip header
-	_, err = r.Read()
-	if err != nil {
-		return fmt.Errorf("failed to skip header: %w", err)
-	}
-
-	for {
-		l, err := r.Read()
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return fmt.Errorf("failed to read line: %w", err)
+		var err error
+		if l.tx != nil {
+			_, err = l.tx.Exec(q)
+		} else {
+			_, err = l.db.Exec(q)
 		}
-
-		_, err = db.Exec(`
-			insert into product (id, name) values ($1, $2)
-		`, l[0], l[1])
 		if err != nil {
-			return fmt.Errorf("failed to insert line to DB: %w", err)
+			return nil, fmt.Errorf("failed to truncate tables: %w", err)
 		}
-	}
-
-	return nil
-}
 
-var capacityRe = regexp.MustCompile(
-	`^(?:(?P<days>\d+) days?|(?P<time>\d+:\d+:\d+)(?:\.(?P<ms>\d+))?|(?P<days2>\d+) days?, (?P<time2>\d+:\d+:\d+)(?:\.(?P<ms2>\d+))?|00:00.(?P<ms3>\d+)|(?P<part>0,\d+)||(?P<zero>0))$`)
-
-func parseDuration(s string) (d time.Duration, err error) {
-	matches := capacityRe.FindStringSubmatch(s)
-	for i, k := range capacityRe.SubexpNames() {
-		if i == 0 || k == "" {
-			continue
-		}
-		if matches[i] == "" {
-			continue
-		}
-		v := matches[i]
-		switch k {
-		case "days", "days2":
-			var days int
-			days, err = strconv.Atoi(v)
-			if err != nil {
-				err = fmt.Errorf("failed to parse days: %w", err)
-				return
-			}
-			d += time.Duration(days) * 24 * time.Hour
-		case "time", "time2":
-			parts := strings.Split(v, ":")
-			if len(parts) != 3 {
-				err = fmt.Errorf("time doesn't have 3 parts")
-				return
-			}
-			var hour, minute, second int
-			hour, err = strconv.Atoi(parts[0])
-			if err != nil {
-				err = fmt.Errorf("failed to parse time hour: %w", err)
-				return
-			}
-			minute, err = strconv.Atoi(parts[1])
-			if err != nil {
-				err = fmt.Errorf("failed to parse time minute: %w", err)
-				return
-			}
-			second, err = strconv.Atoi(parts[2])
-			if err != nil {
-				err = fmt.Errorf("failed to parse time second: %w", err)
-				return
-			}
-			d += time.Duration(hour)*time.Hour +
-				time.Duration(minute)*time.Minute +
-				time.Duration(second)*time.Second
-		case "ms", "ms2", "ms3":
-			var ms int
-			ms, err = strconv.Atoi(v)
-			if err != nil {
-				err = fmt.Errorf("failed to parse ms: %w", err)
-				return
-			}
-			d += time.Duration(ms) * time.Millisecond
-		case "part":
-			v = strings.Replace(v, ",", ".", 1)
-			var part float64
-			part, err = strconv.ParseFloat(v, 64)
-			if err != nil {
-				err = fmt.Errorf("failed to parse part: %w", err)
-				return
-			}
-			d += time.Duration(math.Round(float64(24*time.Hour) * part))
-			return
-		case "zero":
-			return
-		}
+		logrus.WithField("tables", names).Info("tables truncated")
 	}
-	return
-}
 
-func loadResourceGroupPeriod(dataPath string, db *sqlx.DB) error {
-	f, err := os.Open(path.Join(dataPath, "resource-group-period.csv"))
-	if err != nil {
-		return fmt.Errorf("failed to open data file: %w", err)
-	}
+	stats := map[string]postgres.MergeStats{}
 
-	defer func() {
-		err := f.Close()
+	for _, t := range toLoad {
+		s, err := l.load(t)
 		if err != nil {
-			logrus.WithError(err).Error("failed to close data file")
+			return stats, fmt.Errorf("failed to load %s: %w", t.Name, err)
 		}
-	}()
 
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = 7
+		stats[t.Name] = s
 
-	// skip header
-	_, err = r.Read()
-	if err != nil {
-		return fmt.Errorf("failed to skip header: %w", err)
+		logrus.Info(t.Name + " loaded")
 	}
 
-	for {
-		l, err := r.Read()
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return fmt.Errorf("failed to read line: %w", err)
-		}
-
-		id := l[2]
-		resourceGroupID := l[1]
+	return stats, nil
+}
 
-		availableCapacity, err := parseDuration(l[3])
-		if err != nil {
-			return fmt.Errorf("parse available capacity `%s`: %w", l[3], err)
+func (l *loader) load(t dataset.Table) (postgres.MergeStats, error) {
+	if l.mode == modeInsert {
+		var c *postgres.Copier
+		if l.tx != nil {
+			c = postgres.NewTxCopier(l.tx, t.Name, t.Entity, l.batchSize)
+		} else {
+			c = postgres.NewCopier(l.db, t.Name, t.Entity, l.batchSize)
 		}
 
-		freeCapacity, err := parseDuration(l[4])
+		err := copyErr(c, l.read(t, c.Add))
 		if err != nil {
-			return fmt.Errorf("parse free capacity `%s`: %w", l[4], err)
+			return postgres.MergeStats{}, err
 		}
 
-		startDate, err := time.Parse("2006-01-02 15:04:05", l[5])
-		if err != nil {
-			return fmt.Errorf("parse start date `%s`: %w", l[5], err)
-		}
+		return postgres.MergeStats{Inserted: int64(c.Rows())}, nil
+	}
 
-		hasFinateCapacity, err := strconv.ParseBool(l[6])
+	// Staging table lives only within transaction, so upsert is done in
+	// transaction even in non atomic mode.
+	tx := l.tx
+	if tx == nil {
+		var err error
+		tx, err = l.db.Beginx()
 		if err != nil {
-			return fmt.Errorf("parse has finate capacity `%s`: %w", l[6], err)
+			return postgres.MergeStats{}, fmt.Errorf(
+				"failed to begin transaction: %w", err)
 		}
 
-		_, err = db.Exec(`
-			insert into resource_group_period (
-				id,
-			    resource_group_id,
-			    available_capacity,
-			    free_capacity,
-			    start_date,
-			    has_finate_capacity
-			) values ($1, $2, $3, $4, $5, $6)
-		`, id, resourceGroupID, availableCapacity, freeCapacity, startDate,
-			hasFinateCapacity)
-		if err != nil {
-			return fmt.Errorf("insert line to DB: %w", err)
-		}
+		defer tx.Rollback()
 	}
 
-	return nil
-}
-
-func loadRouting(dataPath string, db *sqlx.DB) error {
-	f, err := os.Open(path.Join(dataPath, "routing.csv"))
+	staging, err := postgres.CreateStaging(tx, t.Name)
 	if err != nil {
-		return fmt.Errorf("failed to open data file: %w", err)
+		return postgres.MergeStats{}, err
 	}
 
-	defer func() {
-		err := f.Close()
-		if err != nil {
-			logrus.WithError(err).Error("failed to close data file")
-		}
-	}()
-
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = 6
+	c := postgres.NewTxCopier(tx, staging, t.Entity, l.batchSize)
 
-	// skip header
-	_, err = r.Read()
+	err = copyErr(c, l.read(t, c.Add))
 	if err != nil {
-		return fmt.Errorf("failed to skip header: %w", err)
+		return postgres.MergeStats{}, err
 	}
 
-	for {
-		l, err := r.Read()
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return fmt.Errorf("failed to read line: %w", err)
-		}
+	stats, err := postgres.Merge(tx, t.Name, staging, c.Columns(), t.Key,
+		l.mode == modeSync)
+	if err != nil {
+		return postgres.MergeStats{}, fmt.Errorf("failed to merge: %w", err)
+	}
 
-		_, err = db.Exec(`
-			insert into routing (id, input_product_id, output_product_id, 
-				input_stocking_point_id, output_stocking_point_id)
-			values ($1, $2, $3, $4, $5)
-		`, l[1], l[2], l[3], l[4], l[5])
+	if tx != l.tx {
+		err = tx.Commit()
 		if err != nil {
-			return fmt.Errorf("failed to insert line to DB: %w", err)
+			return postgres.MergeStats{}, fmt.Errorf(
+				"failed to commit transaction: %w", err)
 		}
 	}
 
-	return nil
+	return stats, nil
 }
 
-func loadRoutingStep(dataPath string, db *sqlx.DB) error {
-	f, err := os.Open(path.Join(dataPath, "routing-step.csv"))
-	if err != nil {
-		return fmt.Errorf("failed to open data file: %w", err)
-	}
+// read reads table and writes malformed lines to table reject file until
+// error budget is exceeded.
+func (l *loader) read(t dataset.Table, fn func(e interface{}) error) error {
+	var (
+		f *os.File
+		w *csv.Writer
+	)
 
 	defer func() {
-		err := f.Close()
+		if f == nil {
+			return
+		}
+		w.Flush()
+		err := w.Error()
 		if err != nil {
-			logrus.WithError(err).Error("failed to close data file")
+			logrus.WithError(err).Error("failed to write reject file")
+		}
+		err = f.Close()
+		if err != nil {
+			logrus.WithError(err).Error("failed to close reject file")
 		}
 	}()
 
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = 7
+	return t.ReadAll(l.dataPath, l.config, fn, func(rErr *dataset.RowError) error {
+		l.errors++
+		l.rejected[t.Name]++
 
-	// skip header
-	_, err = r.Read()
-	if err != nil {
-		return fmt.Errorf("failed to skip header: %w", err)
-	}
+		logrus.WithFields(logrus.Fields{
+			"table": t.Name,
+			"line":  rErr.Line,
+		}).WithError(rErr.Err).Warn("line rejected")
 
-	for {
-		l, err := r.Read()
-		if err != nil {
-			if err == io.EOF {
-				break
+		if f == nil {
+			var err error
+			f, err = os.Create(path.Join(l.rejectsPath,
+				t.Name+".rejects.csv"))
+			if err != nil {
+				return fmt.Errorf("failed to create reject file: %w", err)
 			}
-			return fmt.Errorf("failed to read line: %w", err)
+			w = csv.NewWriter(f)
+			w.Write([]string{"line", "error", "record"})
 		}
 
-		yield, err := strconv.ParseFloat(
-			strings.Replace(l[5], ",", ".", 1), 64)
+		err := w.Write([]string{strconv.Itoa(rErr.Line), rErr.Err.Error(),
+			rErr.Raw})
 		if err != nil {
-			return fmt.Errorf("parse yield `%s`: %w", l[5], err)
+			return fmt.Errorf("failed to write reject file: %w", err)
 		}
 
-		_, err = db.Exec(`
-			insert into routing_step (id, sequence_number, routing_id, 
-			    resource_group_id, yield, plant_id)
-			values ($1, $2, $3, $4, $5, $6)
-		`, l[1], l[2], l[3], l[4], yield, l[6])
-		if err != nil {
-			return fmt.Errorf("failed to insert line to DB: %w", err)
+		if l.maxErrors >= 0 && l.errors > l.maxErrors {
+			return fmt.Errorf("error budget exceeded: %d lines rejected, "+
+				"last at %w", l.errors, rErr)
 		}
-	}
 
-	return nil
+		return nil
+	})
 }
 
-func loadCol(dataPath string, db *sqlx.DB) error {
-	f, err := os.Open(path.Join(dataPath, "col.csv"))
+// copyErr closes copier if reading succeeded or aborts it otherwise.
+func copyErr(c *postgres.Copier, err error) error {
 	if err != nil {
-		return fmt.Errorf("failed to open data file: %w", err)
+		c.Abort()
+		return fmt.Errorf("failed to copy data to DB: %w", err)
 	}
+	return c.Close()
+}
 
-	defer func() {
-		err := f.Close()
-		if err != nil {
-			logrus.WithError(err).Error("failed to close data file")
-		}
-	}()
+// validate prints data quality report of tables to stdout and returns true
+// if all tables are valid.
+func validate(dataPath string, c dataset.Config,
+	tables []dataset.Table) bool {
 
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = 19
+	ok := true
 
-	// skip header
-	_, err = r.Read()
-	if err != nil {
-		return fmt.Errorf("failed to skip header: %w", err)
+	for _, t := range tables {
+		r := dataset.Validate(dataPath, c, t)
+		fmt.Print(r)
+		ok = ok && r.OK()
 	}
 
-	for {
-		l, err := r.Read()
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return fmt.Errorf("failed to read line: %w", err)
-		}
-
-		id := l[1]
-
-		quantity, err := strconv.ParseFloat(
-			strings.Replace(l[2], ",", ".", 1), 64)
-		if err != nil {
-			return fmt.Errorf("parse quantity `%s`: %w", l[2], err)
-		}
-
-		minQuantity, err := strconv.ParseFloat(
-			strings.Replace(l[3], ",", ".", 1), 64)
-		if err != nil {
-			return fmt.Errorf("parse min quantity `%s`: %w", l[3], err)
-		}
-
-		maxQuantity, err := strconv.ParseFloat(
-			strings.Replace(l[4], ",", ".", 1), 64)
-		if err != nil {
-			return fmt.Errorf("parse max quantity `%s`: %w", l[4], err)
-		}
-
-		hasSalesBudgetReservation, err := strconv.ParseBool(l[5])
-		if err != nil {
-			return fmt.Errorf("parse has_sales_budget_reservation "+
-				"`%s`: %w", l[5], err)
-		}
-
-		requiresOrderCombination, err := strconv.ParseBool(l[6])
-		if err != nil {
-			return fmt.Errorf("parse requires_order_combination "+
-				"`%s`: %w", l[6], err)
-		}
-
-		numberOfActiveRoutingChainUpstream, err := strconv.Atoi(l[7])
-		if err != nil {
-			return fmt.Errorf("parse number_of_active_routing_chain_upstream "+
-				"`%s`: %w", l[7], err)
-		}
-
-		selectedShippingShop, err := strconv.Atoi(l[8])
-		if err != nil {
-			return fmt.Errorf("parse selected_shipping_shop `%s`: %w",
-				l[8], err)
-		}
-
-		resultProductType := l[9]
-		deliveryType := l[10]
-		plannedStatus := l[11]
-		routingID := l[12]
-		name := l[13]
-		productID := l[14]
-		productName := l[15]
-
-		latestDesiredDeliveryDate, err := time.Parse("2-01-2006", l[16])
-		if err != nil {
-			return fmt.Errorf("parse latest_desired_delivery_date `%s`: %w",
-				l[14], err)
-		}
+	return ok
+}
 
-		productSpecificationID := l[17]
-		resourceGroupIDs := strings.Split(l[18], ", ")
-
-		_, err = db.Exec(`
-			insert into col (
-				id,
-				routing_id,
-			    product_id,
-			    quantity,
-				min_quantity,
-				max_quantity,
-				has_sales_budget_reservation,
-				requires_order_combination,
-				number_of_active_routing_chain_upstream,
-				selected_shipping_shop,
-				result_product_type,
-				delivery_type,
-				planned_status,
-				name,
-				product_name,
-				latest_desired_delivery_date,
-				product_specification_id,
-				resource_group_ids
-			) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
-			          $14, $15, $16, $17, $18)
-		`, id, routingID, productID, quantity, minQuantity, maxQuantity,
-			hasSalesBudgetReservation, requiresOrderCombination,
-			numberOfActiveRoutingChainUpstream, selectedShippingShop,
-			resultProductType, deliveryType, plannedStatus, name,
-			productName, latestDesiredDeliveryDate, productSpecificationID,
-			pq.Array(resourceGroupIDs))
-		if err != nil {
-			return fmt.Errorf("failed to insert line to DB: %w", err)
+// loaded returns true if table is among tables to load.
+func loaded(toLoad []dataset.Table, table string) bool {
+	for _, t := range toLoad {
+		if t.Name == table {
+			return true
 		}
 	}
-
-	return nil
+	return false
 }
 
-func loadSupplyOrder(dataPath string, db *sqlx.DB) error {
-	f, err := os.Open(path.Join(dataPath, "supply-order.csv"))
-	if err != nil {
-		return fmt.Errorf("failed to open data file: %w", err)
+// checkTruncate returns error if tables are referenced by foreign keys of
+// tables which are not truncated with them, truncate fails then.
+func checkTruncate(db *sqlx.DB, tables []dataset.Table) error {
+	truncated := map[string]bool{}
+	var names []string
+	for _, t := range tables {
+		truncated[t.Name] = true
+		names = append(names, t.Name)
 	}
 
-	defer func() {
-		err := f.Close()
-		if err != nil {
-			logrus.WithError(err).Error("failed to close data file")
-		}
-	}()
-
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = 15
+	var refs []struct {
+		Table      string `db:"table_name"`
+		Referenced string `db:"referenced_name"`
+	}
 
-	// skip header
-	_, err = r.Read()
+	err := db.Select(&refs, `
+		select distinct conrelid::regclass::text as table_name,
+			confrelid::regclass::text as referenced_name
+		from pg_constraint
+		where contype = 'f' and confrelid::regclass::text = any($1)
+		order by 1, 2
+	`, pq.Array(names))
 	if err != nil {
-		return fmt.Errorf("failed to skip header: %w", err)
+		return fmt.Errorf("select foreign keys: %w", err)
 	}
 
-	for {
-		l, err := r.Read()
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return fmt.Errorf("failed to read line: %w", err)
-		}
-
-		quantity, err := strconv.ParseFloat(
-			strings.Replace(l[6], ",", ".", 1), 64)
-		if err != nil {
-			return fmt.Errorf("parse quantity `%s`: %w", l[6], err)
-		}
-
-		startTime, err := time.Parse("2006-01-02 15:04:05", l[9])
-		if err != nil {
-			return fmt.Errorf("parse start_time `%s`: %w", l[9], err)
-		}
-
-		endTime, err := time.Parse("2006-01-02 15:04:05", l[10])
-		if err != nil {
-			return fmt.Errorf("parse end_time `%s`: %w", l[10], err)
-		}
-
-		deadlineTime, err := time.Parse("2006-01-02 15:04:05", l[11])
-		if err != nil {
-			return fmt.Errorf("parse deadline_time `%s`: %w", l[11], err)
+	var blockers []string
+	for _, r := range refs {
+		if !truncated[r.Table] {
+			blockers = append(blockers, r.Table+" references "+r.Referenced)
 		}
+	}
 
-		_, err = db.Exec(`
-			insert into supply_order (id, product_id, order_position,
-				product_name, product_type, quantity, stocking_point_id,
-			    planned_status, start_time, end_time, deadline_time,
-			    product_full_id, routing_id, col_id)
-			    values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12,
-			            $13, $14)
-		`, l[1], l[2], l[3], l[4], l[5], quantity, l[7], l[8],
-			startTime, endTime, deadlineTime, l[12], l[13], l[14])
-		if err != nil {
-			return fmt.Errorf("failed to insert line to DB: %w", err)
-		}
+	if len(blockers) > 0 {
+		return fmt.Errorf("%s; load referencing tables too or don't "+
+			"truncate", strings.Join(blockers, ", "))
 	}
 
 	return nil
 }
 
-func loadSupplyOrderOperation(dataPath string, db *sqlx.DB) error {
-	f, err := os.Open(path.Join(dataPath, "supply-order-operation.csv"))
-	if err != nil {
-		return fmt.Errorf("failed to open data file: %w", err)
-	}
+// tableKeys returns those of keys which are ids of table rows.
+func tableKeys(db *sqlx.DB, table string, keys []string) (
+	map[string]bool, error) {
 
-	defer func() {
-		err := f.Close()
-		if err != nil {
-			logrus.WithError(err).Error("failed to close data file")
-		}
-	}()
-
-	r := csv.NewReader(f)
-	r.FieldsPerRecord = 14
+	var ids []string
 
-	// skip header
-	_, err = r.Read()
+	err := db.Select(&ids, `select id from `+table+` where id = any($1)`,
+		pq.Array(keys))
 	if err != nil {
-		return fmt.Errorf("failed to skip header: %w", err)
+		return nil, fmt.Errorf("select %s ids: %w", table, err)
 	}
 
-	for {
-		l, err := r.Read()
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return fmt.Errorf("failed to read line: %w", err)
-		}
-
-		sequenceNumber, err := strconv.Atoi(l[3])
-		if err != nil {
-			return fmt.Errorf("parse sequence_number `%s`: %w",
-				l[3], err)
-		}
-
-		startTimeParts := strings.Split(l[5], ".")
-		startTime, err := time.Parse("Jan-2-2006 15:04:05",
-			startTimeParts[0])
-		if err != nil {
-			return fmt.Errorf("parse start_time `%s`: %w", l[5], err)
-		}
-		if len(startTimeParts) > 1 {
-			ms, err := strconv.Atoi(startTimeParts[1])
-			if err != nil {
-				return fmt.Errorf("parse start_time ms part `%s`: %w",
-					l[5], err)
-			}
-			startTime.Add(time.Duration(ms) * time.Millisecond)
-		}
-
-		endTime, err := time.Parse("2006-01-02 15:04:05", l[6])
-		if err != nil {
-			return fmt.Errorf("parse end_time `%s`: %w", l[6], err)
-		}
-
-		productionTime, err := parseDuration(l[7])
-		if err != nil {
-			return fmt.Errorf("parse production_time `%s`: %w", l[7], err)
-		}
+	found := map[string]bool{}
+	for _, id := range ids {
+		found[id] = true
+	}
 
-		inputQuantity, err := strconv.ParseFloat(strings.Replace(l[8], ",", ".", 1), 64)
-		if err != nil {
-			return fmt.Errorf("parse input_quantity `%s: %w", l[8], err)
-		}
+	return found, nil
+}
 
-		outputQuantity, err := strconv.ParseFloat(strings.Replace(l[9], ",", ".", 1), 64)
-		if err != nil {
-			return fmt.Errorf("parse output_quantity `%s: %w", l[9], err)
-		}
+// printOrphans prints orphan references to stdout.
+func printOrphans(orphans []dataset.Orphans) {
+	if len(orphans) == 0 {
+		return
+	}
 
-		schedulingSpace, err := parseDuration(l[10])
-		if err != nil {
-			return fmt.Errorf("parse scheduling_space `%s`: %w", l[10], err)
-		}
+	fmt.Println("orphan references")
+	for _, o := range orphans {
+		fmt.Printf("  %s\n", o)
+	}
+}
 
-		operationCode, err := strconv.Atoi(l[12])
-		if err != nil {
-			return fmt.Errorf("parse operation_code `%s`: %w", l[12], err)
-		}
+// report prints per table counts of affected and rejected rows to stdout.
+func report(loaded []dataset.Table, stats map[string]postgres.MergeStats,
+	rejected map[string]int) {
 
-		_, err = db.Exec(`
-			insert into supply_order_operation (id, description,
-			    sequence_number, allowed_standard_resources,
-			    start_time, end_time, production_time, input_quantity,
-			    output_quantity, scheduling_space, resource_group_id,
-				operation_code, routing_step_id)
-			values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-		`, l[1], l[2], sequenceNumber, l[4], startTime, endTime, productionTime,
-			inputQuantity, outputQuantity, schedulingSpace, l[11],
-			operationCode, l[13])
-		if err != nil {
-			return fmt.Errorf("failed to insert line to DB: %w", err)
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w,
+		"TABLE\tINSERTED\tUPDATED\tUNCHANGED\tDELETED\tREJECTED\t")
+	for _, t := range loaded {
+		s, ok := stats[t.Name]
+		if !ok {
+			continue
 		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t\n", t.Name, s.Inserted,
+			s.Updated, s.Unchanged, s.Deleted, rejected[t.Name])
 	}
 
-	return nil
+	err := w.Flush()
+	if err != nil {
+		logrus.WithError(err).Error("failed to print report")
+	}
 }
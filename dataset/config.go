@@ -0,0 +1,94 @@
+package dataset
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Config configures reading of tables. Zero config parses timestamps in UTC
+// with layouts of Tables.
+type Config struct {
+	// Location is time zone of timestamps which have no zone, UTC if nil.
+	Location *time.Location
+	// TimeLayouts replaces layouts of timestamp columns given as
+	// table.column, for example supply_order.start_time.
+	TimeLayouts map[string][]string
+}
+
+// NewConfig returns config with time zone given by IANA name, for example
+// Europe/Moscow, and with layouts of timestamp columns.
+func NewConfig(timezone string, layouts map[string][]string) (
+	Config, error) {
+
+	var c Config
+
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return Config{}, fmt.Errorf("load location: %w", err)
+		}
+		c.Location = loc
+	}
+
+	for column, ls := range layouts {
+		err := checkTimeColumn(column, ls)
+		if err != nil {
+			return Config{}, err
+		}
+	}
+	c.TimeLayouts = layouts
+
+	return c, nil
+}
+
+func (c Config) location() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
+
+// parser returns parser of column of table taking layouts of config into
+// account.
+func (c Config) parser(t Table, col Column) Parser {
+	if ls, exists := c.TimeLayouts[t.Name+"."+col.DB]; exists {
+		return Timestamp(ls...)
+	}
+	return col.Parse
+}
+
+// checkTimeColumn returns error if column given as table.column isn't
+// timestamp column of Tables or has no layouts.
+func checkTimeColumn(column string, layouts []string) error {
+	if len(layouts) == 0 {
+		return fmt.Errorf("column %s has no layouts", column)
+	}
+
+	parts := strings.SplitN(column, ".", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("column %s is not in table.column form", column)
+	}
+
+	t, exists := TableByName(parts[0])
+	if !exists {
+		return fmt.Errorf("unknown column %s", column)
+	}
+
+	for _, c := range append(t.Columns, t.Optional...) {
+		if c.DB != parts[1] {
+			continue
+		}
+
+		f := fieldByTag(reflect.New(reflect.TypeOf(t.Entity)).Elem(), c.DB)
+		if f.Type() != reflect.TypeOf(time.Time{}) {
+			return errors.New("column " + column + " is not timestamp")
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown column %s", column)
+}
@@ -0,0 +1,102 @@
+package dataset
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+const periods = `resource_group_id,id,available_capacity,free_capacity,` +
+	`start_date,has_finate_capacity
+RG1,RGP1,1 day,12:00:00,2020-01-02 03:00:00,true
+`
+
+func readStartDate(t *testing.T, dataPath string, c Config) time.Time {
+	t.Helper()
+
+	table, _ := TableByName("resource_group_period")
+
+	var start time.Time
+
+	err := table.Read(dataPath, c, func(e interface{}) error {
+		start = e.(entity.ResourceGroupPeriod).StartDate
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	return start
+}
+
+func TestConfig(t *testing.T) {
+	dataPath, err := ioutil.TempDir("", "dataset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataPath)
+
+	err = ioutil.WriteFile(path.Join(dataPath, "resource-group-period.csv"),
+		[]byte(periods), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	utc := time.Date(2020, 1, 2, 3, 0, 0, 0, time.UTC)
+
+	if got := readStartDate(t, dataPath, Config{}); !got.Equal(utc) {
+		t.Errorf("zero config: got %s, want %s", got, utc)
+	}
+
+	moscow, err := NewConfig("Europe/Moscow", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := utc.Add(-3 * time.Hour)
+	if got := readStartDate(t, dataPath, moscow); !got.Equal(want) {
+		t.Errorf("Europe/Moscow: got %s, want %s", got, want)
+	}
+
+	// Config of previous read doesn't leak into next one.
+	if got := readStartDate(t, dataPath, Config{}); !got.Equal(utc) {
+		t.Errorf("zero config after Europe/Moscow: got %s, want %s", got,
+			utc)
+	}
+
+	layouts, err := NewConfig("", map[string][]string{
+		"resource_group_period.start_date": {"2006-02-01 15:04:05"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want = time.Date(2020, 2, 1, 3, 0, 0, 0, time.UTC)
+	if got := readStartDate(t, dataPath, layouts); !got.Equal(want) {
+		t.Errorf("layouts: got %s, want %s", got, want)
+	}
+}
+
+func TestNewConfigErrors(t *testing.T) {
+	for name, layouts := range map[string]map[string][]string{
+		"unknown table":  {"foo.start_date": {time.RFC3339}},
+		"unknown column": {"supply_order.foo": {time.RFC3339}},
+		"not timestamp":  {"supply_order.quantity": {time.RFC3339}},
+		"no table":       {"start_date": {time.RFC3339}},
+		"no layouts":     {"supply_order.start_time": nil},
+	} {
+		_, err := NewConfig("", layouts)
+		if err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+
+	_, err := NewConfig("Nowhere/Nothing", nil)
+	if err == nil {
+		t.Error("unknown time zone: no error")
+	}
+}
@@ -0,0 +1,322 @@
+// Package dataset reads planning data exported from ERP as CSV files.
+// CSV columns are mapped to entity fields by header names using declarative
+// per-table mapping, see Tables.
+package dataset
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Column maps CSV column to entity field.
+type Column struct {
+	// CSV is CSV header name, it is matched case insensitive.
+	CSV string
+	// DB is table column which is db tag of entity field.
+	DB    string
+	Parse Parser
+}
+
+// Table describes how entities of DB table are read from CSV file.
+type Table struct {
+	Name string
+	File string
+	// Key is natural key of table.
+	Key []string
+	// Distinct makes reader to skip rows with already seen key. It is used
+	// for tables which are denormalized in CSV files.
+	Distinct bool
+	// Entity is zero value of entity read from table.
+	Entity  interface{}
+	Columns []Column
+	// Optional lists columns which are read only if CSV file has them.
+	Optional []Column
+	// Ignore lists CSV columns which are known but not read to the table.
+	Ignore []string
+	// Enums lists columns with small set of values which distribution is
+	// reported by Validate.
+	Enums []string
+	// References lists columns referencing keys of other tables.
+	References []Reference
+	// Resolve returns resolver which fills entity fields not read from CSV
+	// file. It is called once per read.
+	Resolve func(dataPath string, c Config) (Resolver, error)
+}
+
+// Resolver returns entity with fields derived from other data. Its error
+// rejects line.
+type Resolver func(e interface{}) (interface{}, error)
+
+// RowError is error of parsing single CSV line.
+type RowError struct {
+	Line   int
+	Record []string
+	// Raw is original text of line without trailing newline.
+	Raw string
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Read reads table from its CSV file in dataPath with config c and calls fn
+// with each entity. Entity is passed by value. Reading is aborted on first
+// malformed line with *RowError.
+func (t Table) Read(dataPath string, c Config,
+	fn func(e interface{}) error) error {
+
+	return t.ReadAll(dataPath, c, fn, func(err *RowError) error {
+		return err
+	})
+}
+
+// ReadAll is like Read but passes malformed lines to onErr and goes on.
+// Reading is aborted if onErr returns error.
+func (t Table) ReadAll(dataPath string, c Config, fn func(e interface{}) error,
+	onErr func(*RowError) error) error {
+	return t.read(dataPath, c, func(_ int, e interface{}) error {
+		return fn(e)
+	}, onErr)
+}
+
+func (t Table) read(dataPath string, c Config,
+	fn func(line int, e interface{}) error,
+	onErr func(*RowError) error) error {
+
+	f, err := os.Open(path.Join(dataPath, t.File))
+	if err != nil {
+		return fmt.Errorf("failed to open data file: %w", err)
+	}
+
+	defer func() {
+		err := f.Close()
+		if err != nil {
+			logrus.WithError(err).Error("failed to close data file")
+		}
+	}()
+
+	r := csv.NewReader(f)
+
+	header, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+
+	m, err := t.mapping(header, c)
+	if err != nil {
+		return err
+	}
+
+	var resolve Resolver
+	if t.Resolve != nil {
+		resolve, err = t.Resolve(dataPath, c)
+		if err != nil {
+			return fmt.Errorf("failed to prepare resolver: %w", err)
+		}
+	}
+
+	seen := map[string]bool{}
+
+	for {
+		start := r.InputOffset()
+
+		l, err := r.Read()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			if pErr, ok := err.(*csv.ParseError); ok {
+				err = onErr(&RowError{
+					Line:   pErr.StartLine,
+					Record: l,
+					Raw:    raw(f, start, r.InputOffset()),
+					Err:    pErr.Err,
+				})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("failed to read line: %w", err)
+		}
+
+		line, _ := r.FieldPos(0)
+
+		e, key, err := m.parse(l)
+		if err == nil && resolve != nil {
+			e, err = resolve(e)
+		}
+		if err != nil {
+			err = onErr(&RowError{
+				Line:   line,
+				Record: l,
+				Raw:    raw(f, start, r.InputOffset()),
+				Err:    err,
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if t.Distinct {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+
+		err = fn(line, e)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// raw returns text of file between offsets without trailing newline.
+func raw(f *os.File, start, end int64) string {
+	b := make([]byte, end-start)
+
+	n, err := f.ReadAt(b, start)
+	if err != nil && err != io.EOF {
+		logrus.WithError(err).Error("failed to read raw line")
+	}
+
+	return strings.TrimRight(string(b[:n]), "\r\n")
+}
+
+// mapping is table columns bound to CSV header.
+type mapping struct {
+	config  Config
+	entity  reflect.Type
+	columns []Column
+	indexes []int // CSV column index per column
+	fields  []int // entity field index per column
+	keys    []int // CSV column index per key column
+}
+
+func (t Table) mapping(header []string, cfg Config) (*mapping, error) {
+	m := &mapping{
+		config: cfg,
+		entity: reflect.TypeOf(t.Entity),
+	}
+
+	fields := map[string]int{}
+	for i := 0; i < m.entity.NumField(); i++ {
+		tag := m.entity.Field(i).Tag.Get("db")
+		if tag != "" && tag != "-" {
+			fields[tag] = i
+		}
+	}
+
+	csvIndexes := map[string]int{}
+	for i, h := range header {
+		csvIndexes[normalizeHeader(h)] = i
+	}
+
+	used := map[int]bool{}
+
+	for _, h := range t.Ignore {
+		if i, exists := csvIndexes[normalizeHeader(h)]; exists {
+			used[i] = true
+		}
+	}
+
+	var missing []string
+
+	for ci, c := range append(t.Columns, t.Optional...) {
+		f, exists := fields[c.DB]
+		if !exists {
+			return nil, fmt.Errorf("entity %s has no field for column %s",
+				m.entity.Name(), c.DB)
+		}
+
+		i, exists := csvIndexes[normalizeHeader(c.CSV)]
+		if !exists {
+			if ci < len(t.Columns) {
+				missing = append(missing, c.CSV)
+			}
+			continue
+		}
+
+		used[i] = true
+
+		c.Parse = cfg.parser(t, c)
+
+		m.columns = append(m.columns, c)
+		m.indexes = append(m.indexes, i)
+		m.fields = append(m.fields, f)
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%s misses columns: %s", t.File,
+			strings.Join(missing, ", "))
+	}
+
+	for _, k := range t.Key {
+		for ci, c := range m.columns {
+			if c.DB == k {
+				m.keys = append(m.keys, m.indexes[ci])
+			}
+		}
+	}
+
+	var extra []string
+
+	for i, h := range header {
+		// Unnamed column is row index added by export, it is expected.
+		if !used[i] && strings.TrimSpace(h) != "" {
+			extra = append(extra, h)
+		}
+	}
+
+	if len(extra) > 0 {
+		logrus.WithFields(logrus.Fields{
+			"file":    t.File,
+			"columns": strings.Join(extra, ", "),
+		}).Warn("extra columns are ignored")
+	}
+
+	return m, nil
+}
+
+// parse parses CSV line to entity and returns it with its key.
+func (m *mapping) parse(l []string) (interface{}, string, error) {
+	e := reflect.New(m.entity).Elem()
+
+	for ci, c := range m.columns {
+		s := l[m.indexes[ci]]
+
+		v, err := c.Parse(s, m.config)
+		if err != nil {
+			return nil, "", fmt.Errorf("parse %s `%s`: %w", c.CSV, s, err)
+		}
+
+		e.Field(m.fields[ci]).Set(reflect.ValueOf(v))
+	}
+
+	var key []string
+	for _, k := range m.keys {
+		key = append(key, l[k])
+	}
+
+	return e.Interface(), strings.Join(key, "\x00"), nil
+}
+
+func normalizeHeader(h string) string {
+	return strings.ToLower(strings.TrimSpace(h))
+}
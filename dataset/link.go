@@ -0,0 +1,133 @@
+package dataset
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+// linkSupplyOrders returns resolver of supply orders of operations which
+// are read without supply_order_id column. Operation is linked to supply
+// order with routing of its routing step. If there are several such orders
+// the one which time window contains operation start is chosen.
+func linkSupplyOrders(dataPath string, c Config) (Resolver, error) {
+	stepRoutings := map[string]string{}
+
+	err := routingStepTable.ReadAll(dataPath, c, func(e interface{}) error {
+		s := e.(entity.RoutingStep)
+		stepRoutings[s.ID] = s.RoutingID
+		return nil
+	}, skipRowError)
+	if err != nil {
+		return nil, fmt.Errorf("read routing steps: %w", err)
+	}
+
+	orders := map[string][]entity.SupplyOrder{}
+
+	err = supplyOrderTable.ReadAll(dataPath, c, func(e interface{}) error {
+		so := e.(entity.SupplyOrder)
+		orders[so.RoutingID] = append(orders[so.RoutingID], so)
+		return nil
+	}, skipRowError)
+	if err != nil {
+		return nil, fmt.Errorf("read supply orders: %w", err)
+	}
+
+	return func(e interface{}) (interface{}, error) {
+		op := e.(entity.SupplyOrderOperation)
+		if op.SupplyOrderID != "" {
+			return op, nil
+		}
+
+		routingID, exists := stepRoutings[op.RoutingStepID]
+		if !exists {
+			return nil, fmt.Errorf("link supply order: routing step %s "+
+				"not found", op.RoutingStepID)
+		}
+
+		candidates := orders[routingID]
+
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("link supply order: no supply order "+
+				"with routing %s", routingID)
+		}
+
+		if len(candidates) > 1 {
+			var inWindow []entity.SupplyOrder
+			for _, so := range candidates {
+				if !op.StartTime.Before(so.StartTime) &&
+					op.StartTime.Before(so.EndTime) {
+					inWindow = append(inWindow, so)
+				}
+			}
+			candidates = inWindow
+		}
+
+		switch len(candidates) {
+		case 0:
+			return nil, fmt.Errorf("link supply order: no supply order "+
+				"with routing %s runs at operation start %s", routingID,
+				op.StartTime)
+		case 1:
+			op.SupplyOrderID = candidates[0].ID
+			return op, nil
+		default:
+			return nil, fmt.Errorf("link supply order: %d supply orders "+
+				"with routing %s match operation start at %s",
+				len(candidates), routingID, op.StartTime)
+		}
+	}, nil
+}
+
+// inferPlants returns resolver of plants of resource groups which are read
+// without plant_id column. Plant is taken from routing steps done by resource
+// group, they all must be done at the same plant.
+func inferPlants(dataPath string, c Config) (Resolver, error) {
+	plants := map[string]map[string]bool{}
+
+	err := routingStepTable.ReadAll(dataPath, c, func(e interface{}) error {
+		s := e.(entity.RoutingStep)
+		if plants[s.ResourceGroupID] == nil {
+			plants[s.ResourceGroupID] = map[string]bool{}
+		}
+		plants[s.ResourceGroupID][s.PlantID] = true
+		return nil
+	}, skipRowError)
+	if err != nil {
+		return nil, fmt.Errorf("read routing steps: %w", err)
+	}
+
+	return func(e interface{}) (interface{}, error) {
+		rg := e.(entity.ResourceGroup)
+		if rg.PlantID != "" {
+			return rg, nil
+		}
+
+		var ids []string
+		for id := range plants[rg.ID] {
+			ids = append(ids, id)
+		}
+
+		switch len(ids) {
+		case 0:
+			return nil, fmt.Errorf("infer plant: no routing steps of "+
+				"resource group %s", rg.ID)
+		case 1:
+			rg.PlantID = ids[0]
+			return rg, nil
+		default:
+			sort.Strings(ids)
+			return nil, fmt.Errorf("infer plant: routing steps of resource "+
+				"group %s are done at several plants: %s", rg.ID,
+				strings.Join(ids, ", "))
+		}
+	}, nil
+}
+
+// skipRowError ignores malformed lines, they are reported when table itself
+// is read.
+func skipRowError(*RowError) error {
+	return nil
+}
@@ -0,0 +1,43 @@
+package dataset
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dimuls/mipt-hack-accenture/duration"
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+// Parser parses CSV value to value of entity field type. Config is config
+// of read.
+type Parser func(s string, c Config) (interface{}, error)
+
+func String(s string, _ Config) (interface{}, error) {
+	return s, nil
+}
+
+func Int(s string, _ Config) (interface{}, error) {
+	return strconv.Atoi(s)
+}
+
+// Float parses float with decimal comma.
+func Float(s string, _ Config) (interface{}, error) {
+	return strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+}
+
+func Bool(s string, _ Config) (interface{}, error) {
+	return strconv.ParseBool(s)
+}
+
+// Duration parses duration in one of formats of duration package.
+func Duration(s string, _ Config) (interface{}, error) {
+	d, err := duration.Parse(s)
+	return entity.Duration(d), err
+}
+
+// StringList returns parser of strings list separated by sep.
+func StringList(sep string) Parser {
+	return func(s string, _ Config) (interface{}, error) {
+		return strings.Split(s, sep), nil
+	}
+}
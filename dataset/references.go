@@ -0,0 +1,180 @@
+package dataset
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Reference is column referencing key of another table.
+type Reference struct {
+	Column string
+	Table  string
+}
+
+// Orphans is references of table column to keys missing in referenced table.
+type Orphans struct {
+	Table    string
+	Column   string
+	RefTable string
+	// Rows is number of rows with orphan reference.
+	Rows int
+	// Keys is sorted distinct missing keys.
+	Keys []string
+}
+
+func (o Orphans) String() string {
+	return fmt.Sprintf("%s.%s -> %s: %d rows, missing keys: %q", o.Table,
+		o.Column, o.RefTable, o.Rows, o.Keys)
+}
+
+// Keys reads table from dataPath with config c and returns set of its keys.
+// Malformed lines are skipped since they are reported by Validate.
+func (t Table) Keys(dataPath string, c Config) (map[string]bool, error) {
+	keys := map[string]bool{}
+
+	err := t.ReadAll(dataPath, c, func(e interface{}) error {
+		keys[t.key(reflect.ValueOf(e))] = true
+		return nil
+	}, skipRowError)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// FindOrphans reads tables from dataPath with config c and returns their
+// references to keys missing both in read tables and in keys confirmed by
+// known. Every table is read once, keys are collected only for referenced
+// tables. Function known is called once per referenced table with sorted
+// keys missing in read tables and returns those of them which exist
+// elsewhere, it may return nil.
+func FindOrphans(dataPath string, c Config, tables []Table,
+	known func(table string, keys []string) (map[string]bool, error)) (
+	[]Orphans, error) {
+
+	referenced := map[string]bool{}
+	for _, t := range tables {
+		for _, r := range t.References {
+			referenced[r.Table] = true
+		}
+	}
+
+	// read holds keys of read tables which are referenced.
+	read := map[string]map[string]bool{}
+
+	// values holds number of rows by referencing value of every reference
+	// of every table.
+	values := map[string][]map[string]int{}
+
+	for _, t := range tables {
+		collectKeys := referenced[t.Name]
+		if !collectKeys && len(t.References) == 0 {
+			continue
+		}
+
+		keys := map[string]bool{}
+		refValues := make([]map[string]int, len(t.References))
+		for i := range refValues {
+			refValues[i] = map[string]int{}
+		}
+
+		err := t.ReadAll(dataPath, c, func(e interface{}) error {
+			v := reflect.ValueOf(e)
+			if collectKeys {
+				keys[t.key(v)] = true
+			}
+			for i, r := range t.References {
+				refValues[i][fmt.Sprint(fieldByTag(v, r.Column).Interface())]++
+			}
+			return nil
+		}, skipRowError)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", t.Name, err)
+		}
+
+		if collectKeys {
+			read[t.Name] = keys
+		}
+		values[t.Name] = refValues
+	}
+
+	// missing holds values of references to table missing in read keys.
+	missing := map[string]map[string]bool{}
+
+	for _, t := range tables {
+		for i, r := range t.References {
+			if missing[r.Table] == nil {
+				missing[r.Table] = map[string]bool{}
+			}
+			for v := range values[t.Name][i] {
+				if !read[r.Table][v] {
+					missing[r.Table][v] = true
+				}
+			}
+		}
+	}
+
+	// found holds keys which are missing in read tables but exist elsewhere.
+	found := map[string]map[string]bool{}
+
+	var refTables []string
+	for table := range missing {
+		refTables = append(refTables, table)
+	}
+	sort.Strings(refTables)
+
+	for _, table := range refTables {
+		if len(missing[table]) == 0 {
+			continue
+		}
+
+		var keys []string
+		for k := range missing[table] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		k, err := known(table, keys)
+		if err != nil {
+			return nil, fmt.Errorf("get %s keys: %w", table, err)
+		}
+		found[table] = k
+	}
+
+	var orphans []Orphans
+
+	for _, t := range tables {
+		for i, r := range t.References {
+			o := Orphans{Table: t.Name, Column: r.Column, RefTable: r.Table}
+
+			for v, rows := range values[t.Name][i] {
+				if read[r.Table][v] || found[r.Table][v] {
+					continue
+				}
+				o.Rows += rows
+				o.Keys = append(o.Keys, v)
+			}
+
+			if o.Rows == 0 {
+				continue
+			}
+
+			sort.Strings(o.Keys)
+			orphans = append(orphans, o)
+		}
+	}
+
+	return orphans, nil
+}
+
+// key returns key of entity as it is referenced by other tables.
+func (t Table) key(v reflect.Value) string {
+	var key []string
+	for _, k := range t.Key {
+		key = append(key, fmt.Sprint(fieldByTag(v, k).Interface()))
+	}
+	return strings.Join(key, ", ")
+}
@@ -0,0 +1,252 @@
+package dataset
+
+import (
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+// Default layouts of timestamps, see Config.TimeLayouts.
+const (
+	timeLayout          = "2006-01-02 15:04:05"
+	dateLayout          = "2-01-2006"
+	operationTimeLayout = "Jan-2-2006 15:04:05"
+)
+
+// Tables lists mapping of all tables in load order.
+var Tables = []Table{
+	{
+		Name:   "plant",
+		File:   "plant.csv",
+		Key:    []string{"id"},
+		Entity: entity.Plant{},
+		Columns: []Column{
+			{"id", "id", String},
+			{"name", "name", String},
+			{"description", "description", String},
+		},
+	},
+	{
+		Name:   "stocking_point",
+		File:   "stocking-point.csv",
+		Key:    []string{"id"},
+		Entity: entity.StockingPoint{},
+		Columns: []Column{
+			{"id", "id", String},
+			{"name", "name", String},
+		},
+	},
+	{
+		// resource-group.csv has line per resource.
+		Name:     "resource_group",
+		File:     "resource-group.csv",
+		Key:      []string{"id"},
+		Distinct: true,
+		Entity:   entity.ResourceGroup{},
+		References: []Reference{
+			{"plant_id", "plant"},
+		},
+		Columns: []Column{
+			{"resource_group_id", "id", String},
+			{"name", "name", String},
+		},
+		// Plant is inferred from routing steps if file has no plant_id.
+		Optional: []Column{
+			{"plant_id", "plant_id", String},
+		},
+		Ignore:  []string{"resource_id", "short_name", "long_name"},
+		Resolve: inferPlants,
+	},
+	{
+		Name:   "resource",
+		File:   "resource-group.csv",
+		Key:    []string{"id"},
+		Entity: entity.Resource{},
+		References: []Reference{
+			{"resource_group_id", "resource_group"},
+		},
+		Columns: []Column{
+			{"resource_id", "id", String},
+			{"resource_group_id", "resource_group_id", String},
+			{"short_name", "short_name", String},
+			{"long_name", "long_name", String},
+		},
+		Ignore: []string{"name", "plant_id"},
+	},
+	{
+		Name:   "product",
+		File:   "product.csv",
+		Key:    []string{"id"},
+		Entity: entity.Product{},
+		Columns: []Column{
+			{"id", "id", String},
+			{"name", "name", String},
+		},
+	},
+	{
+		Name:   "resource_group_period",
+		File:   "resource-group-period.csv",
+		Key:    []string{"id"},
+		Entity: entity.ResourceGroupPeriod{},
+		References: []Reference{
+			{"resource_group_id", "resource_group"},
+		},
+		Columns: []Column{
+			{"id", "id", String},
+			{"resource_group_id", "resource_group_id", String},
+			{"available_capacity", "available_capacity", Duration},
+			{"free_capacity", "free_capacity", Duration},
+			{"start_date", "start_date", Timestamp(timeLayout)},
+			{"has_finate_capacity", "has_finate_capacity", Bool},
+		},
+	},
+	{
+		Name:   "routing",
+		File:   "routing.csv",
+		Key:    []string{"id"},
+		Entity: entity.Routing{},
+		References: []Reference{
+			{"output_product_id", "product"},
+			{"input_stocking_point_id", "stocking_point"},
+			{"output_stocking_point_id", "stocking_point"},
+		},
+		Columns: []Column{
+			{"id", "id", String},
+			{"input_product_id", "input_product_id", String},
+			{"output_product_id", "output_product_id", String},
+			{"input_stocking_point_id", "input_stocking_point_id", String},
+			{"output_stocking_point_id", "output_stocking_point_id", String},
+		},
+	},
+	routingStepTable,
+	{
+		Name:   "col",
+		File:   "col.csv",
+		Key:    []string{"id"},
+		Entity: entity.Col{},
+		References: []Reference{
+			{"routing_id", "routing"},
+			{"product_id", "product"},
+		},
+		Enums: []string{"result_product_type", "delivery_type",
+			"planned_status"},
+		Columns: []Column{
+			{"id", "id", String},
+			{"quantity", "quantity", Float},
+			{"min_quantity", "min_quantity", Float},
+			{"max_quantity", "max_quantity", Float},
+			{"has_sales_budget_reservation", "has_sales_budget_reservation",
+				Bool},
+			{"requires_order_combination", "requires_order_combination",
+				Bool},
+			{"number_of_active_routing_chain_upstream",
+				"number_of_active_routing_chain_upstream", Int},
+			{"selected_shipping_shop", "selected_shipping_shop", Int},
+			{"result_product_type", "result_product_type", String},
+			{"delivery_type", "delivery_type", String},
+			{"planned_status", "planned_status", String},
+			{"routing_id", "routing_id", String},
+			{"name", "name", String},
+			{"product_id", "product_id", String},
+			{"product_name", "product_name", String},
+			{"latest_desired_delivery_date", "latest_desired_delivery_date",
+				Timestamp(dateLayout)},
+			{"product_specification_id", "product_specification_id", String},
+			{"resource_group_ids", "resource_group_ids", StringList(", ")},
+		},
+	},
+	supplyOrderTable,
+	{
+		Name:   "supply_order_operation",
+		File:   "supply-order-operation.csv",
+		Key:    []string{"id"},
+		Entity: entity.SupplyOrderOperation{},
+		References: []Reference{
+			{"supply_order_id", "supply_order"},
+			{"resource_group_id", "resource_group"},
+			{"routing_step_id", "routing_step"},
+		},
+		Columns: []Column{
+			{"id", "id", String},
+			{"description", "description", String},
+			{"sequence_number", "sequence_number", Int},
+			{"allowed_standard_resources", "allowed_standard_resources",
+				String},
+			{"start_time", "start_time", Timestamp(operationTimeLayout)},
+			{"end_time", "end_time", Timestamp(timeLayout)},
+			{"production_time", "production_time", Duration},
+			{"input_quantity", "input_quantity", Float},
+			{"output_quantity", "output_quantity", Float},
+			{"scheduling_space", "scheduling_space", Duration},
+			{"resource_group_id", "resource_group_id", String},
+			{"operation_code", "operation_code", Int},
+			{"routing_step_id", "routing_step_id", String},
+		},
+		Optional: []Column{
+			{"supply_order_id", "supply_order_id", String},
+		},
+		Resolve: linkSupplyOrders,
+	},
+}
+
+// Tables read by resolvers are declared apart from Tables to avoid
+// initialization cycle.
+var (
+	routingStepTable = Table{
+		Name:   "routing_step",
+		File:   "routing-step.csv",
+		Key:    []string{"id"},
+		Entity: entity.RoutingStep{},
+		References: []Reference{
+			{"plant_id", "plant"},
+			{"routing_id", "routing"},
+			{"resource_group_id", "resource_group"},
+		},
+		Columns: []Column{
+			{"id", "id", String},
+			{"sequence_number", "sequence_number", Int},
+			{"routing_id", "routing_id", String},
+			{"resource_group_id", "resource_group_id", String},
+			{"yield", "yield", Float},
+			{"plant_id", "plant_id", String},
+		},
+	}
+
+	supplyOrderTable = Table{
+		Name:   "supply_order",
+		File:   "supply-order.csv",
+		Key:    []string{"id"},
+		Entity: entity.SupplyOrder{},
+		References: []Reference{
+			{"col_id", "col"},
+			{"routing_id", "routing"},
+			{"product_id", "product"},
+			{"stocking_point_id", "stocking_point"},
+		},
+		Enums: []string{"product_type", "planned_status"},
+		Columns: []Column{
+			{"id", "id", String},
+			{"product_id", "product_id", String},
+			{"order_position", "order_position", String},
+			{"product_name", "product_name", String},
+			{"product_type", "product_type", String},
+			{"quantity", "quantity", Float},
+			{"stocking_point_id", "stocking_point_id", String},
+			{"planned_status", "planned_status", String},
+			{"start_time", "start_time", Timestamp(timeLayout)},
+			{"end_time", "end_time", Timestamp(timeLayout)},
+			{"deadline_time", "deadline_time", Timestamp(timeLayout)},
+			{"product_full_id", "product_full_id", String},
+			{"routing_id", "routing_id", String},
+			{"col_id", "col_id", String},
+		},
+	}
+)
+
+// TableByName returns table mapping by table name.
+func TableByName(name string) (Table, bool) {
+	for _, t := range Tables {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Table{}, false
+}
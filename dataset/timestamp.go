@@ -0,0 +1,28 @@
+package dataset
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Timestamp returns parser of time in first matching layout. Time is parsed
+// in time zone of config. Fractional seconds after seconds are accepted in
+// any layout, so "00:00:01.5" is parsed as 1.5 seconds.
+func Timestamp(layouts ...string) Parser {
+	return func(s string, c Config) (interface{}, error) {
+		var err error
+		for _, l := range layouts {
+			var t time.Time
+			t, err = time.ParseInLocation(l, s, c.location())
+			if err == nil {
+				return t, nil
+			}
+		}
+		if len(layouts) > 1 {
+			return nil, fmt.Errorf("no layout of %s matches",
+				strings.Join(layouts, ", "))
+		}
+		return nil, err
+	}
+}
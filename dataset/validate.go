@@ -0,0 +1,133 @@
+package dataset
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Report is data quality report of table CSV file.
+type Report struct {
+	Table string
+	File  string
+	// Err is set if file can't be read at all, e.g. it misses columns.
+	Err      error
+	Rows     int
+	Rejected []*RowError
+	// Values is distribution of values by enum column.
+	Values map[string]map[string]int
+}
+
+// Validate parses table CSV file in dataPath with config c and reports its
+// data quality. Besides malformed lines it rejects lines with duplicate key.
+func Validate(dataPath string, c Config, t Table) Report {
+	r := Report{
+		Table:  t.Name,
+		File:   t.File,
+		Values: map[string]map[string]int{},
+	}
+
+	for _, c := range t.Enums {
+		r.Values[c] = map[string]int{}
+	}
+
+	keyLines := map[string]int{}
+
+	r.Err = t.read(dataPath, c, func(line int, e interface{}) error {
+		r.Rows++
+
+		v := reflect.ValueOf(e)
+
+		if !t.Distinct && len(t.Key) > 0 {
+			k := t.key(v)
+
+			if first, seen := keyLines[k]; seen {
+				r.Rejected = append(r.Rejected, &RowError{
+					Line: line,
+					Err: fmt.Errorf("duplicate key (%s) first seen at "+
+						"line %d", k, first),
+				})
+				return nil
+			}
+
+			keyLines[k] = line
+		}
+
+		for _, c := range t.Enums {
+			r.Values[c][fmt.Sprint(fieldByTag(v, c).Interface())]++
+		}
+
+		return nil
+	}, func(err *RowError) error {
+		r.Rows++
+		r.Rejected = append(r.Rejected, err)
+		return nil
+	})
+
+	return r
+}
+
+// String returns human readable report.
+func (r Report) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s (%s)\n", r.Table, r.File)
+
+	if r.Err != nil {
+		fmt.Fprintf(&b, "  failed: %s\n", r.Err)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "  rows: %d, valid: %d, rejected: %d\n", r.Rows,
+		r.Rows-len(r.Rejected), len(r.Rejected))
+
+	for _, err := range r.Rejected {
+		fmt.Fprintf(&b, "    %s\n", err)
+	}
+
+	var columns []string
+	for c := range r.Values {
+		columns = append(columns, c)
+	}
+
+	sort.Strings(columns)
+
+	for _, c := range columns {
+		fmt.Fprintf(&b, "  %s:\n", c)
+
+		var values []string
+		for v := range r.Values[c] {
+			values = append(values, v)
+		}
+
+		sort.Slice(values, func(i, j int) bool {
+			ci, cj := r.Values[c][values[i]], r.Values[c][values[j]]
+			if ci != cj {
+				return ci > cj
+			}
+			return values[i] < values[j]
+		})
+
+		for _, v := range values {
+			fmt.Fprintf(&b, "    %q: %d\n", v, r.Values[c][v])
+		}
+	}
+
+	return b.String()
+}
+
+// OK returns true if table is read without any rejected line.
+func (r Report) OK() bool {
+	return r.Err == nil && len(r.Rejected) == 0
+}
+
+func fieldByTag(v reflect.Value, tag string) reflect.Value {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("db") == tag {
+			return v.Field(i)
+		}
+	}
+	panic("no field with db tag " + tag + " in " + t.Name())
+}
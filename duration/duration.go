@@ -0,0 +1,342 @@
+// Package duration parses and formats durations found in ERP exports and
+// Postgres intervals.
+//
+// Parse accepts the following formats, every field may be signed:
+//
+//	""                    zero
+//	"0", "2"              days
+//	"0,25", "0.25"        fractional days
+//	"3 days", "1 day"     days
+//	"08:30:00.5"          hours, minutes, seconds with optional fraction
+//	"00:00.5"             minutes, seconds with optional fraction
+//	"2 days, 08:30:00"    days and time as exported by ERP
+//	"-1 days +02:00:00"   days and time as output by Postgres
+//
+// Fraction of seconds is decimal, so "00:00:01.5" is 1.5 seconds. Years and
+// months are rejected since their length varies.
+package duration
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// Parse parses duration in one of formats listed in package doc.
+func Parse(s string) (time.Duration, error) {
+	in := strings.TrimSpace(s)
+	if in == "" {
+		return 0, nil
+	}
+
+	if isNumber(in) {
+		days, err := strconv.ParseFloat(strings.Replace(in, ",", ".", 1), 64)
+		if err != nil {
+			return 0, fmt.Errorf("parse duration %q: %w", s, err)
+		}
+		return time.Duration(math.Round(days * float64(day))), nil
+	}
+
+	fields := strings.Fields(strings.Replace(in, ",", " ", 1))
+
+	var (
+		d         time.Duration
+		seenDays  bool
+		seenClock bool
+	)
+
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+
+		if strings.Contains(f, ":") {
+			if seenClock {
+				return 0, fmt.Errorf("parse duration %q: time is repeated", s)
+			}
+			seenClock = true
+
+			c, err := parseClock(f)
+			if err != nil {
+				return 0, fmt.Errorf("parse duration %q: %w", s, err)
+			}
+
+			d += c
+			continue
+		}
+
+		if seenDays || seenClock || i+1 >= len(fields) {
+			return 0, fmt.Errorf("parse duration %q: unexpected %q", s, f)
+		}
+
+		unit := fields[i+1]
+		i++
+
+		switch unit {
+		case "day", "days":
+		case "year", "years", "mon", "mons", "month", "months":
+			return 0, fmt.Errorf("parse duration %q: years and months are "+
+				"not supported", s)
+		default:
+			return 0, fmt.Errorf("parse duration %q: unknown unit %q", s,
+				unit)
+		}
+
+		seenDays = true
+
+		n, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parse duration %q: invalid days: %w", s,
+				err)
+		}
+
+		d += time.Duration(n) * day
+	}
+
+	return d, nil
+}
+
+// isNumber returns true if s is signed decimal number with dot or comma.
+func isNumber(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	if s == "" {
+		return false
+	}
+
+	sep := false
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+		case (r == ',' || r == '.') && !sep && i > 0 && i < len(s)-1:
+			sep = true
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseClock parses [+-][HH:]MM:SS[.fraction].
+func parseClock(s string) (time.Duration, error) {
+	neg := strings.HasPrefix(s, "-")
+	abs := strings.TrimLeft(s, "+-")
+
+	parts := strings.Split(abs, ":")
+
+	var h, m int64
+	var sec string
+
+	switch len(parts) {
+	case 2:
+		sec = parts[1]
+	case 3:
+		var err error
+		h, err = parseUint(parts[0])
+		if err != nil {
+			return 0, fmt.Errorf("invalid hours in %q: %w", s, err)
+		}
+		sec = parts[2]
+	default:
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+
+	m, err := parseUint(parts[len(parts)-2])
+	if err != nil {
+		return 0, fmt.Errorf("invalid minutes in %q: %w", s, err)
+	}
+	if m >= 60 {
+		return 0, fmt.Errorf("minutes out of range in %q", s)
+	}
+
+	secParts := strings.SplitN(sec, ".", 2)
+
+	wholeSec, err := parseUint(secParts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid seconds in %q: %w", s, err)
+	}
+	if wholeSec >= 60 {
+		return 0, fmt.Errorf("seconds out of range in %q", s)
+	}
+
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(wholeSec)*time.Second
+
+	if len(secParts) == 2 {
+		frac := secParts[1]
+		if _, err := parseUint(frac); err != nil {
+			return 0, fmt.Errorf("invalid fraction of seconds in %q: %w",
+				s, err)
+		}
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+		ns, _ := strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)),
+			10, 64)
+		d += time.Duration(ns)
+	}
+
+	if neg {
+		d = -d
+	}
+
+	return d, nil
+}
+
+func parseUint(s string) (int64, error) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, fmt.Errorf("%q is not unsigned integer", s)
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// Interval formats duration as Postgres interval "[-]HH:MM:SS[.ffffff]".
+// Hours are not carried to days since day of interval is calendar one.
+// Interval precision is microsecond, so nanoseconds are truncated.
+func Interval(d time.Duration) string {
+	var b strings.Builder
+
+	u := uint64(d)
+	if d < 0 {
+		b.WriteByte('-')
+		u = -u
+	}
+
+	h := u / uint64(time.Hour)
+	u -= h * uint64(time.Hour)
+	m := u / uint64(time.Minute)
+	u -= m * uint64(time.Minute)
+	sec := u / uint64(time.Second)
+	u -= sec * uint64(time.Second)
+
+	fmt.Fprintf(&b, "%02d:%02d:%02d", h, m, sec)
+
+	if us := u / uint64(time.Microsecond); us > 0 {
+		b.WriteString(strings.TrimRight(fmt.Sprintf(".%06d", us), "0"))
+	}
+
+	return b.String()
+}
+
+// ISO formats duration as ISO-8601 duration, for example "PT8H30M". Hours are
+// not carried to days since day of ISO-8601 is calendar one.
+func ISO(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+
+	u := uint64(d)
+	if d < 0 {
+		b.WriteByte('-')
+		u = -u
+	}
+
+	b.WriteString("PT")
+
+	h := u / uint64(time.Hour)
+	u -= h * uint64(time.Hour)
+	m := u / uint64(time.Minute)
+	u -= m * uint64(time.Minute)
+
+	if h > 0 {
+		b.WriteString(strconv.FormatUint(h, 10) + "H")
+	}
+	if m > 0 {
+		b.WriteString(strconv.FormatUint(m, 10) + "M")
+	}
+	if u > 0 {
+		sec := float64(u) / float64(time.Second)
+		b.WriteString(strconv.FormatFloat(sec, 'f', -1, 64) + "S")
+	}
+
+	return b.String()
+}
+
+// ParseISO parses ISO-8601 duration with optional leading minus. Days are
+// considered to be 24 hours long, years and months are rejected.
+func ParseISO(s string) (time.Duration, error) {
+	in := s
+
+	neg := strings.HasPrefix(in, "-")
+	if neg {
+		in = in[1:]
+	}
+
+	if !strings.HasPrefix(in, "P") || len(in) < 2 {
+		return 0, fmt.Errorf("parse ISO-8601 duration %q: no P designator",
+			s)
+	}
+	in = in[1:]
+
+	units := map[byte]time.Duration{
+		'W': 7 * day,
+		'D': day,
+	}
+
+	var (
+		d        float64
+		timePart bool
+	)
+
+	for in != "" {
+		if in[0] == 'T' {
+			if timePart {
+				return 0, fmt.Errorf("parse ISO-8601 duration %q: T "+
+					"designator is repeated", s)
+			}
+			timePart = true
+			units = map[byte]time.Duration{
+				'H': time.Hour,
+				'M': time.Minute,
+				'S': time.Second,
+			}
+			in = in[1:]
+			if in == "" {
+				return 0, fmt.Errorf("parse ISO-8601 duration %q: empty "+
+					"time part", s)
+			}
+			continue
+		}
+
+		i := strings.IndexFunc(in, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.' && r != ','
+		})
+		if i <= 0 {
+			return 0, fmt.Errorf("parse ISO-8601 duration %q: number "+
+				"expected", s)
+		}
+
+		unit, exists := units[in[i]]
+		if !exists {
+			return 0, fmt.Errorf("parse ISO-8601 duration %q: unexpected "+
+				"designator %c", s, in[i])
+		}
+
+		v, err := strconv.ParseFloat(strings.Replace(in[:i], ",", ".", 1),
+			64)
+		if err != nil {
+			return 0, fmt.Errorf("parse ISO-8601 duration %q: %w", s, err)
+		}
+
+		d += v * float64(unit)
+
+		// Every designator may appear once and in order.
+		for u := range units {
+			if units[u] >= unit {
+				delete(units, u)
+			}
+		}
+
+		in = in[i+1:]
+	}
+
+	if neg {
+		d = -d
+	}
+
+	return time.Duration(math.Round(d)), nil
+}
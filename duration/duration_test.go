@@ -0,0 +1,188 @@
+package duration
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"  ", 0},
+		{"0", 0},
+		{"2", 48 * time.Hour},
+		{"-1", -24 * time.Hour},
+		{"0,25", 6 * time.Hour},
+		{"0.25", 6 * time.Hour},
+		{"+0.5", 12 * time.Hour},
+		{"3 days", 72 * time.Hour},
+		{"1 day", 24 * time.Hour},
+		{"-2 days", -48 * time.Hour},
+		{"08:30:00", 8*time.Hour + 30*time.Minute},
+		{"08:30:00.5", 8*time.Hour + 30*time.Minute + 500*time.Millisecond},
+		{"00:00:01.123456789", time.Second + 123456789},
+		{"00:00:01.1234567891", time.Second + 123456789},
+		{"36:00:00", 36 * time.Hour},
+		{"-01:00:00", -time.Hour},
+		{"00:00.5", 500 * time.Millisecond},
+		{"12:30", 12*time.Minute + 30*time.Second},
+		{"2 days, 08:30:00", 56*time.Hour + 30*time.Minute},
+		{"1 day 01:00:00", 25 * time.Hour},
+		{"-1 days +02:00:00", -22 * time.Hour},
+		{"1 days -02:00:00", 22 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"abc",
+		"1,",
+		",5",
+		"1.2.3",
+		"--1",
+		"1 week",
+		"1 year",
+		"2 mons",
+		"days",
+		"1 day 2 days",
+		"01:00:00 01:00:00",
+		"01:00:00 1 day",
+		"1:2:3:4",
+		"01:60:00",
+		"01:00:60",
+		"aa:00:00",
+		"00:aa:00",
+		"00:00:aa",
+		"00:00:01.x",
+		"00:00:01.",
+		"x days",
+	}
+
+	for _, in := range tests {
+		if d, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) = %v, want error", in, d)
+		}
+	}
+}
+
+func TestInterval(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "00:00:00"},
+		{8*time.Hour + 30*time.Minute, "08:30:00"},
+		{36 * time.Hour, "36:00:00"},
+		{-time.Hour, "-01:00:00"},
+		{1500 * time.Millisecond, "00:00:01.5"},
+		{time.Second + 123456789, "00:00:01.123456"},
+		{999, "00:00:00"},
+	}
+
+	for _, tt := range tests {
+		if got := Interval(tt.in); got != tt.want {
+			t.Errorf("Interval(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalRoundTrip(t *testing.T) {
+	for _, d := range []time.Duration{0, time.Hour, -90 * time.Minute,
+		49*time.Hour + 1500*time.Millisecond} {
+
+		got, err := Parse(Interval(d))
+		if err != nil {
+			t.Errorf("Parse(Interval(%v)) error: %v", d, err)
+			continue
+		}
+		if got != d {
+			t.Errorf("Parse(Interval(%v)) = %v", d, got)
+		}
+	}
+}
+
+func TestISO(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "PT0S"},
+		{8*time.Hour + 30*time.Minute, "PT8H30M"},
+		{36 * time.Hour, "PT36H"},
+		{-time.Hour, "-PT1H"},
+		{1500 * time.Millisecond, "PT1.5S"},
+		{time.Hour + time.Second, "PT1H1S"},
+	}
+
+	for _, tt := range tests {
+		if got := ISO(tt.in); got != tt.want {
+			t.Errorf("ISO(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseISO(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"PT0S", 0},
+		{"PT8H30M", 8*time.Hour + 30*time.Minute},
+		{"PT1.5S", 1500 * time.Millisecond},
+		{"PT0,5H", 30 * time.Minute},
+		{"P1D", 24 * time.Hour},
+		{"P1W", 7 * 24 * time.Hour},
+		{"P1DT2H", 26 * time.Hour},
+		{"-PT1H", -time.Hour},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseISO(tt.in)
+		if err != nil {
+			t.Errorf("ParseISO(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseISO(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseISOErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"P",
+		"1H",
+		"PT",
+		"P1DT",
+		"PT1HT2H",
+		"P1Y",
+		"P1M",
+		"PT1H2H",
+		"PT1M1H",
+		"P1H",
+		"PTH",
+		"PT1X",
+		"PT1.2.3S",
+	}
+
+	for _, in := range tests {
+		if d, err := ParseISO(in); err == nil {
+			t.Errorf("ParseISO(%q) = %v, want error", in, d)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/duration"
+)
+
+// Duration is time.Duration which is stored as Postgres interval and is
+// marshaled to JSON as ISO-8601 duration, for example "PT8H30M".
+type Duration time.Duration
+
+// Hours returns duration as floating point number of hours.
+func (d Duration) Hours() float64 {
+	return time.Duration(d).Hours()
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(duration.ISO(time.Duration(d)))
+}
+
+// UnmarshalJSON accepts ISO-8601 duration or number of hours.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var v interface{}
+
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case string:
+		pd, err := duration.ParseISO(v)
+		if err != nil {
+			return err
+		}
+		*d = Duration(pd)
+		return nil
+	case float64:
+		*d = Duration(math.Round(v * float64(time.Hour)))
+		return nil
+	}
+
+	return fmt.Errorf("duration must be ISO-8601 string or number of hours")
+}
+
+func (d Duration) Value() (driver.Value, error) {
+	return duration.Interval(time.Duration(d)), nil
+}
+
+// Scan scans interval in Postgres output style, for example
+// "1 day 02:00:00.5".
+func (d *Duration) Scan(src interface{}) error {
+	var s string
+
+	switch src := src.(type) {
+	case []byte:
+		s = string(src)
+	case string:
+		s = src
+	default:
+		return fmt.Errorf("can't scan %T to duration", src)
+	}
+
+	pd, err := duration.Parse(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(pd)
+
+	return nil
+}
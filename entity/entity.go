@@ -14,8 +14,11 @@ type StockingPoint struct {
 }
 
 type ResourceGroup struct {
-	ID   string `db:"id" json:"id"`
-	Name string `db:"name" json:"name"`
+	ID        string                `db:"id" json:"id"`
+	PlantID   string                `db:"plant_id" json:"plant_id"`
+	Name      string                `db:"name" json:"name"`
+	Resources []Resource            `db:"-" json:"resources,omitempty"`
+	Periods   []ResourceGroupPeriod `db:"-" json:"periods,omitempty"`
 }
 
 type Resource struct {
@@ -31,12 +34,12 @@ type Product struct {
 }
 
 type ResourceGroupPeriod struct {
-	ID                string        `db:"id" json:"id"`
-	ResourceGroupID   string        `db:"resource_group_id" json:"resource_group_id"`
-	AvailableCapacity time.Duration `db:"available_capacity" json:"available_capacity"`
-	FreeCapacity      time.Duration `db:"free_capacity" json:"free_capacity"`
-	StartDate         time.Time     `db:"start_date" json:"start_date"`
-	HasFinateCapacity bool          `db:"has_finate_capacity" json:"has_finate_capacity"`
+	ID                string    `db:"id" json:"id"`
+	ResourceGroupID   string    `db:"resource_group_id" json:"resource_group_id"`
+	AvailableCapacity Duration  `db:"available_capacity" json:"available_capacity"`
+	FreeCapacity      Duration  `db:"free_capacity" json:"free_capacity"`
+	StartDate         time.Time `db:"start_date" json:"start_date"`
+	HasFinateCapacity bool      `db:"has_finate_capacity" json:"has_finate_capacity"`
 }
 
 type Routing struct {
@@ -95,18 +98,18 @@ type SupplyOrder struct {
 }
 
 type SupplyOrderOperation struct {
-	ID                       string        `db:"id" json:"id"`
-	SupplyOrderID            string        `db:"supply_order_id" json:"supply_order_id"`
-	ResourceGroupID          string        `db:"resource_group_id" json:"resource_group_id"`
-	RoutingStepID            string        `db:"routing_step_id" json:"routing_step_id"`
-	Description              string        `db:"description" json:"description"`
-	SequenceNumber           int           `db:"sequence_number" json:"sequence_number"`
-	AllowedStandardResources string        `db:"allowed_standard_resources" json:"allowed_standard_resources"`
-	StartTime                time.Time     `db:"start_time" json:"start_time"`
-	EndTime                  time.Time     `db:"end_time" json:"end_time"`
-	ProductTime              time.Duration `db:"production_time" json:"production_time"`
-	InputQuantity            float64       `db:"input_quantity" json:"input_quantity"`
-	OutputQuantity           float64       `db:"output_quantity" json:"output_quantity"`
-	SchedulingSpace          time.Duration `db:"scheduling_space" json:"scheduling_space"`
-	OperationCode            int           `db:"operation_code" json:"operation_code"`
+	ID                       string    `db:"id" json:"id"`
+	SupplyOrderID            string    `db:"supply_order_id" json:"supply_order_id"`
+	ResourceGroupID          string    `db:"resource_group_id" json:"resource_group_id"`
+	RoutingStepID            string    `db:"routing_step_id" json:"routing_step_id"`
+	Description              string    `db:"description" json:"description"`
+	SequenceNumber           int       `db:"sequence_number" json:"sequence_number"`
+	AllowedStandardResources string    `db:"allowed_standard_resources" json:"allowed_standard_resources"`
+	StartTime                time.Time `db:"start_time" json:"start_time"`
+	EndTime                  time.Time `db:"end_time" json:"end_time"`
+	ProductTime              Duration  `db:"production_time" json:"production_time"`
+	InputQuantity            float64   `db:"input_quantity" json:"input_quantity"`
+	OutputQuantity           float64   `db:"output_quantity" json:"output_quantity"`
+	SchedulingSpace          Duration  `db:"scheduling_space" json:"scheduling_space"`
+	OperationCode            int       `db:"operation_code" json:"operation_code"`
 }
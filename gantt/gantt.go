@@ -0,0 +1,173 @@
+// Package gantt builds Gantt chart of supply order operations by resource
+// groups and resources.
+package gantt
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+// palette are colors of planned statuses in order of their names.
+var palette = []string{"#4e79a7", "#f28e2b", "#59a14f", "#e15759",
+	"#76b7b2", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"}
+
+// Bar is operation on chart.
+type Bar struct {
+	OperationID     string          `json:"operation_id"`
+	SupplyOrderID   string          `json:"supply_order_id"`
+	OperationCode   int             `json:"operation_code"`
+	Description     string          `json:"description"`
+	ProductID       string          `json:"product_id"`
+	ProductName     string          `json:"product_name"`
+	PlannedStatus   string          `json:"planned_status"`
+	Color           string          `json:"color"`
+	Start           time.Time       `json:"start"`
+	End             time.Time       `json:"end"`
+	ProductionTime  entity.Duration `json:"production_time"`
+	SchedulingSpace entity.Duration `json:"scheduling_space"`
+}
+
+// Lane is row of chart. Operation is put on lane of the first of its
+// allowed standard resources which belongs to resource group, operations
+// without such resource are put on lane without resource.
+type Lane struct {
+	ResourceID string `json:"resource_id,omitempty"`
+	Name       string `json:"name"`
+	Bars       []Bar  `json:"bars"`
+}
+
+// Group is resource group with its lanes.
+type Group struct {
+	ResourceGroupID string `json:"resource_group_id"`
+	Name            string `json:"name"`
+	Lanes           []Lane `json:"lanes"`
+}
+
+// Status is planned status with its color.
+type Status struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// Chart is Gantt chart within [From, To).
+type Chart struct {
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Groups   []Group   `json:"groups"`
+	Statuses []Status  `json:"statuses"`
+}
+
+// Input is resource group with its operations.
+type Input struct {
+	entity.ResourceGroup
+	Operations []entity.SupplyOrderOperation
+}
+
+// New builds chart of resource groups, orders are supply orders of
+// operations by id.
+func New(from, to time.Time, in []Input,
+	orders map[string]entity.SupplyOrder) Chart {
+
+	c := Chart{
+		From:     from,
+		To:       to,
+		Groups:   []Group{},
+		Statuses: statuses(in, orders),
+	}
+
+	colors := map[string]string{}
+	for _, s := range c.Statuses {
+		colors[s.Name] = s.Color
+	}
+
+	for _, rg := range in {
+		g := Group{ResourceGroupID: rg.ID, Name: rg.Name, Lanes: []Lane{}}
+
+		lanes := map[string]int{}
+		for _, r := range rg.Resources {
+			lanes[r.ID] = len(g.Lanes)
+			g.Lanes = append(g.Lanes, Lane{ResourceID: r.ID,
+				Name: r.ShortName, Bars: []Bar{}})
+		}
+
+		unassigned := Lane{Name: "-", Bars: []Bar{}}
+
+		for _, op := range rg.Operations {
+			so := orders[op.SupplyOrderID]
+
+			b := Bar{
+				OperationID:     op.ID,
+				SupplyOrderID:   op.SupplyOrderID,
+				OperationCode:   op.OperationCode,
+				Description:     op.Description,
+				ProductID:       so.ProductID,
+				ProductName:     so.ProductName,
+				PlannedStatus:   so.PlannedStatus,
+				Color:           colors[so.PlannedStatus],
+				Start:           op.StartTime,
+				End:             op.EndTime,
+				ProductionTime:  op.ProductTime,
+				SchedulingSpace: op.SchedulingSpace,
+			}
+
+			li, assigned := lane(op.AllowedStandardResources, lanes)
+			if assigned {
+				g.Lanes[li].Bars = append(g.Lanes[li].Bars, b)
+			} else {
+				unassigned.Bars = append(unassigned.Bars, b)
+			}
+		}
+
+		if len(unassigned.Bars) > 0 {
+			g.Lanes = append(g.Lanes, unassigned)
+		}
+
+		c.Groups = append(c.Groups, g)
+	}
+
+	return c
+}
+
+// lane returns index of lane of the first allowed resource which has lane.
+func lane(allowed string, lanes map[string]int) (int, bool) {
+	ids := strings.FieldsFunc(allowed, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' '
+	})
+
+	for _, id := range ids {
+		if i, exists := lanes[id]; exists {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// statuses returns planned statuses of operations sorted by name with their
+// colors.
+func statuses(in []Input, orders map[string]entity.SupplyOrder) []Status {
+	seen := map[string]bool{}
+	var names []string
+
+	for _, rg := range in {
+		for _, op := range rg.Operations {
+			s := orders[op.SupplyOrderID].PlannedStatus
+			if !seen[s] {
+				seen[s] = true
+				names = append(names, s)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	ss := make([]Status, len(names))
+	for i, n := range names {
+		ss[i] = Status{Name: n, Color: palette[i%len(palette)]}
+	}
+
+	return ss
+}
@@ -0,0 +1,59 @@
+package gantt
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+// Load loads chart of resource group with id, or of all resource groups if
+// id is empty, within [from, to).
+func Load(st storage.Storage, id string, from, to time.Time) (Chart, error) {
+	var rgs []entity.ResourceGroup
+
+	// Chart needs resources of resource groups but not their periods.
+	if id != "" {
+		rg, err := st.ResourceGroups.Get(id)
+		if err != nil {
+			return Chart{}, fmt.Errorf("get resource group %s: %w", id, err)
+		}
+		rgs = []entity.ResourceGroup{rg}
+	} else {
+		var err error
+		rgs, err = st.ResourceGroups.List()
+		if err != nil {
+			return Chart{}, fmt.Errorf("list resource groups: %w", err)
+		}
+	}
+
+	var (
+		in     []Input
+		orders = map[string]entity.SupplyOrder{}
+	)
+
+	for _, rg := range rgs {
+		ops, err := st.SupplyOrderOperations.ByResourceGroup(rg.ID, from, to)
+		if err != nil {
+			return Chart{}, fmt.Errorf("list operations of resource group "+
+				"%s: %w", rg.ID, err)
+		}
+
+		for _, op := range ops {
+			if _, loaded := orders[op.SupplyOrderID]; loaded {
+				continue
+			}
+			so, err := st.SupplyOrders.Get(op.SupplyOrderID)
+			if err != nil {
+				return Chart{}, fmt.Errorf("get supply order %s: %w",
+					op.SupplyOrderID, err)
+			}
+			orders[so.ID] = so
+		}
+
+		in = append(in, Input{ResourceGroup: rg, Operations: ops})
+	}
+
+	return New(from, to, in, orders), nil
+}
@@ -0,0 +1,172 @@
+package gantt
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Layout of SVG in pixels.
+const (
+	labelWidth  = 160
+	chartWidth  = 1000
+	rowHeight   = 24
+	barHeight   = 16
+	headerRows  = 1
+	legendWidth = 140
+	maxTicks    = 20
+)
+
+// tickSteps are steps of time axis from the smallest one.
+var tickSteps = []time.Duration{time.Hour, 3 * time.Hour, 6 * time.Hour,
+	12 * time.Hour, 24 * time.Hour, 7 * 24 * time.Hour, 28 * 24 * time.Hour}
+
+// WriteSVG writes self-contained SVG rendering of chart to w.
+func WriteSVG(w io.Writer, c Chart) error {
+	bw := bufio.NewWriter(w)
+
+	rows := headerRows
+	for _, g := range c.Groups {
+		rows += 1 + len(g.Lanes)
+	}
+	if legend := len(c.Statuses) + 1; rows < legend {
+		rows = legend
+	}
+
+	width := labelWidth + chartWidth + legendWidth
+	height := rows * rowHeight
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" `+
+		`height="%d" viewBox="0 0 %d %d" font-family="sans-serif" `+
+		`font-size="11">`+"\n", width, height, width, height)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n",
+		width, height)
+
+	x := scale(c.From, c.To)
+
+	step := tickStep(c.To.Sub(c.From))
+	for t := c.From; t.Before(c.To); t = t.Add(step) {
+		fmt.Fprintf(bw, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" `+
+			`stroke="#e0e0e0"/>`+"\n", x(t), rowHeight, x(t), height)
+		fmt.Fprintf(bw, `<text x="%.1f" y="%d">%s</text>`+"\n", x(t)+2,
+			rowHeight-8, escape(t.Format(tickLayout(step))))
+	}
+
+	y := headerRows * rowHeight
+
+	for _, g := range c.Groups {
+		fmt.Fprintf(bw, `<rect x="0" y="%d" width="%d" height="%d" `+
+			`fill="#f0f0f0"/>`+"\n", y, labelWidth+chartWidth, rowHeight)
+		fmt.Fprintf(bw, `<text x="4" y="%d" font-weight="bold">%s</text>`+
+			"\n", y+rowHeight-8, escape(g.Name+" ("+g.ResourceGroupID+")"))
+		y += rowHeight
+
+		for _, l := range g.Lanes {
+			fmt.Fprintf(bw, `<text x="12" y="%d">%s</text>`+"\n",
+				y+rowHeight-8, escape(l.Name))
+
+			for _, b := range l.Bars {
+				writeBar(bw, b, x, y)
+			}
+
+			fmt.Fprintf(bw, `<line x1="0" y1="%d" x2="%d" y2="%d" `+
+				`stroke="#f0f0f0"/>`+"\n", y+rowHeight,
+				labelWidth+chartWidth, y+rowHeight)
+			y += rowHeight
+		}
+	}
+
+	lx := labelWidth + chartWidth + 10
+	fmt.Fprintf(bw, `<text x="%d" y="%d" font-weight="bold">`+
+		`planned status</text>`+"\n", lx, rowHeight-8)
+	for i, s := range c.Statuses {
+		ly := (i + 1) * rowHeight
+		fmt.Fprintf(bw, `<rect x="%d" y="%d" width="12" height="12" `+
+			`fill="%s"/>`+"\n", lx, ly+4, s.Color)
+		fmt.Fprintf(bw, `<text x="%d" y="%d">%s</text>`+"\n", lx+16,
+			ly+rowHeight-8, escape(s.Name))
+	}
+
+	fmt.Fprintln(bw, "</svg>")
+
+	return bw.Flush()
+}
+
+// writeBar writes bar with its scheduling space before it.
+func writeBar(w io.Writer, b Bar, x func(time.Time) float64, y int) {
+	by := y + (rowHeight-barHeight)/2
+
+	if b.SchedulingSpace > 0 {
+		s := b.Start.Add(-time.Duration(b.SchedulingSpace))
+		fmt.Fprintf(w, `<rect x="%.1f" y="%d" width="%.1f" height="%d" `+
+			`fill="#cccccc"/>`+"\n", x(s), by+barHeight/2-1,
+			x(b.Start)-x(s), 2)
+	}
+
+	bx, bw := x(b.Start), x(b.End)-x(b.Start)
+	if bw < 1 {
+		bw = 1
+	}
+
+	title := fmt.Sprintf("%s %d %s\n%s %s\n%s - %s\nproduction time %s, "+
+		"scheduling space %s\n%s", b.OperationID, b.OperationCode,
+		b.Description, b.ProductID, b.ProductName,
+		b.Start.Format("2006-01-02 15:04"), b.End.Format("2006-01-02 15:04"),
+		b.ProductionTime, b.SchedulingSpace, b.PlannedStatus)
+
+	fmt.Fprintf(w, `<g><title>%s</title>`, escape(title))
+	fmt.Fprintf(w, `<rect x="%.1f" y="%d" width="%.1f" height="%d" `+
+		`fill="%s" stroke="#333333" stroke-width="0.5"/>`, bx, by, bw,
+		barHeight, b.Color)
+
+	label := fmt.Sprintf("%d %s", b.OperationCode, b.ProductName)
+	// Label is shown only if it roughly fits bar.
+	if float64(len(label))*6 < bw {
+		fmt.Fprintf(w, `<text x="%.1f" y="%d" fill="#ffffff">%s</text>`,
+			bx+2, by+barHeight-4, escape(label))
+	}
+
+	fmt.Fprintln(w, "</g>")
+}
+
+// scale returns function which maps time to x coordinate, times outside of
+// [from, to) are clamped.
+func scale(from, to time.Time) func(time.Time) float64 {
+	span := float64(to.Sub(from))
+
+	return func(t time.Time) float64 {
+		if t.Before(from) {
+			t = from
+		}
+		if t.After(to) {
+			t = to
+		}
+		return labelWidth + float64(t.Sub(from))/span*chartWidth
+	}
+}
+
+func tickStep(span time.Duration) time.Duration {
+	for _, s := range tickSteps {
+		if span/s <= maxTicks {
+			return s
+		}
+	}
+	return tickSteps[len(tickSteps)-1] * (span/maxTicks/
+		tickSteps[len(tickSteps)-1] + 1)
+}
+
+func tickLayout(step time.Duration) string {
+	if step < 24*time.Hour {
+		return "01-02 15:04"
+	}
+	return "2006-01-02"
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
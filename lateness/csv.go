@@ -0,0 +1,63 @@
+package lateness
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvHeader is header of CSV export, durations are in hours.
+var csvHeader = []string{"col_id", "name", "delivery_type",
+	"result_product_type", "quantity", "min_quantity", "planned_quantity",
+	"in_full", "supply_orders", "late_supply_orders", "due",
+	"projected_end", "lateness_hours", "on_time_probability"}
+
+// WriteCSV writes COLs of report to w, the least probable to be delivered
+// on time first.
+func WriteCSV(w io.Writer, r Report) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write(csvHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, onTime := range []OnTime{None, Low, Medium, High} {
+		for _, c := range r.Cols {
+			if c.OnTime != onTime {
+				continue
+			}
+
+			lateness := ""
+			if c.Lateness != nil {
+				lateness = formatFloat(c.Lateness.Hours())
+			}
+
+			err = cw.Write([]string{c.ColID, c.Name, c.DeliveryType,
+				c.ResultProductType, formatFloat(c.Quantity),
+				formatFloat(c.MinQuantity), formatFloat(c.PlannedQuantity),
+				strconv.FormatBool(c.InFull), strconv.Itoa(c.SupplyOrders),
+				strconv.Itoa(c.LateSupplyOrders), formatTime(c.Due),
+				formatTime(c.ProjectedEnd), lateness, string(c.OnTime)})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,216 @@
+// Package lateness projects lateness of COLs by end times of their supply
+// orders and aggregates probability of on-time-in-full delivery.
+package lateness
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+// DefaultMargin is default slack which COL needs to be delivered on time
+// with high probability.
+const DefaultMargin = 24 * time.Hour
+
+// OnTime is bucket of on-time-in-full probability.
+type OnTime string
+
+const (
+	// High is COL planned in full which ends at least margin before due.
+	High OnTime = "high"
+	// Medium is COL planned in full which ends before due by less than
+	// margin.
+	Medium OnTime = "medium"
+	// Low is COL which is not planned in full or is late by less than
+	// margin.
+	Low OnTime = "low"
+	// None is COL which is late by margin or more or is not planned at all.
+	None OnTime = "none"
+)
+
+// OnTimes are on-time buckets from the most to the least probable.
+var OnTimes = []OnTime{High, Medium, Low, None}
+
+// Col is projected delivery of COL.
+type Col struct {
+	ColID             string  `json:"col_id"`
+	Name              string  `json:"name"`
+	DeliveryType      string  `json:"delivery_type"`
+	ResultProductType string  `json:"result_product_type"`
+	Quantity          float64 `json:"quantity"`
+	MinQuantity       float64 `json:"min_quantity"`
+	PlannedQuantity   float64 `json:"planned_quantity"`
+	InFull            bool    `json:"in_full"`
+	SupplyOrders      int     `json:"supply_orders"`
+	LateSupplyOrders  int     `json:"late_supply_orders"`
+	// Due is latest desired delivery date of COL or the latest deadline of
+	// its supply orders if COL has no date.
+	Due time.Time `json:"due"`
+	// ProjectedEnd is the latest end time of supply orders.
+	ProjectedEnd time.Time `json:"projected_end"`
+	// Lateness is time from due to projected end, it is negative if COL
+	// ends before due and nil if COL is not planned or has no due.
+	Lateness *entity.Duration `json:"lateness"`
+	OnTime   OnTime           `json:"on_time_probability"`
+}
+
+// Group is aggregated lateness of COLs with the same key.
+type Group struct {
+	Key  string `json:"key"`
+	Cols int    `json:"cols"`
+	Late int    `json:"late"`
+	// OnTimeRate is share of COLs planned in full which aren't late, that
+	// is of High and Medium ones.
+	OnTimeRate float64 `json:"on_time_rate"`
+	// AverageLateness and MaxLateness are over late COLs.
+	AverageLateness entity.Duration `json:"average_lateness"`
+	MaxLateness     entity.Duration `json:"max_lateness"`
+	OnTimes         map[OnTime]int  `json:"on_time_probabilities"`
+}
+
+// Report is lateness of COLs with aggregates.
+type Report struct {
+	Cols                []Col   `json:"cols"`
+	ByDeliveryType      []Group `json:"by_delivery_type"`
+	ByResultProductType []Group `json:"by_result_product_type"`
+	ByName              []Group `json:"by_name"`
+}
+
+// Project projects delivery of COL by its supply orders.
+func Project(col entity.Col, sos []entity.SupplyOrder,
+	margin time.Duration) Col {
+
+	c := Col{
+		ColID:             col.ID,
+		Name:              col.Name,
+		DeliveryType:      col.DeliveryType,
+		ResultProductType: col.ResultProductType,
+		Quantity:          col.Quantity,
+		MinQuantity:       col.MinQuantity,
+		SupplyOrders:      len(sos),
+		Due:               col.LatestDesiredDeliveryDate,
+	}
+
+	var latestDeadline time.Time
+
+	for _, so := range sos {
+		c.PlannedQuantity += so.Quantity
+
+		if so.EndTime.After(c.ProjectedEnd) {
+			c.ProjectedEnd = so.EndTime
+		}
+		if so.DeadlineTime.After(latestDeadline) {
+			latestDeadline = so.DeadlineTime
+		}
+		if !so.DeadlineTime.IsZero() && so.EndTime.After(so.DeadlineTime) {
+			c.LateSupplyOrders++
+		}
+	}
+
+	if c.Due.IsZero() {
+		c.Due = latestDeadline
+	}
+
+	min := col.MinQuantity
+	if min == 0 {
+		min = col.Quantity
+	}
+	c.InFull = len(sos) > 0 && c.PlannedQuantity >= min
+
+	if !c.ProjectedEnd.IsZero() && !c.Due.IsZero() {
+		l := entity.Duration(c.ProjectedEnd.Sub(c.Due))
+		c.Lateness = &l
+	}
+
+	c.OnTime = onTime(c, margin)
+
+	return c
+}
+
+func onTime(c Col, margin time.Duration) OnTime {
+	if c.ProjectedEnd.IsZero() {
+		return None
+	}
+
+	var l time.Duration
+	if c.Lateness != nil {
+		l = time.Duration(*c.Lateness)
+	}
+
+	switch {
+	case l >= margin:
+		return None
+	case l > 0 || !c.InFull:
+		return Low
+	case c.Lateness != nil && -l < margin:
+		return Medium
+	}
+	return High
+}
+
+// late returns true if COL is projected to end after due.
+func (c Col) late() bool {
+	return c.Lateness != nil && *c.Lateness > 0
+}
+
+// NewReport aggregates COLs.
+func NewReport(cols []Col) Report {
+	r := Report{Cols: cols}
+	if r.Cols == nil {
+		r.Cols = []Col{}
+	}
+
+	r.ByDeliveryType = aggregate(r.Cols,
+		func(c Col) string { return c.DeliveryType })
+	r.ByResultProductType = aggregate(r.Cols,
+		func(c Col) string { return c.ResultProductType })
+	r.ByName = aggregate(r.Cols, func(c Col) string { return c.Name })
+
+	return r
+}
+
+func aggregate(cols []Col, key func(Col) string) []Group {
+	groups := map[string]*Group{}
+
+	for _, c := range cols {
+		k := key(c)
+
+		g, exists := groups[k]
+		if !exists {
+			g = &Group{Key: k, OnTimes: map[OnTime]int{}}
+			for _, o := range OnTimes {
+				g.OnTimes[o] = 0
+			}
+			groups[k] = g
+		}
+
+		g.Cols++
+		g.OnTimes[c.OnTime]++
+
+		if c.late() {
+			g.Late++
+			g.AverageLateness += *c.Lateness
+			if *c.Lateness > g.MaxLateness {
+				g.MaxLateness = *c.Lateness
+			}
+		}
+	}
+
+	gs := make([]Group, 0, len(groups))
+
+	for _, g := range groups {
+		if g.Late > 0 {
+			g.AverageLateness /= entity.Duration(g.Late)
+		}
+		g.OnTimeRate = float64(g.OnTimes[High]+g.OnTimes[Medium]) /
+			float64(g.Cols)
+		gs = append(gs, *g)
+	}
+
+	sort.Slice(gs, func(i, j int) bool {
+		return gs[i].Key < gs[j].Key
+	})
+
+	return gs
+}
@@ -0,0 +1,69 @@
+package lateness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+var due = time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC)
+
+func so(quantity float64, end time.Time) entity.SupplyOrder {
+	return entity.SupplyOrder{Quantity: quantity, EndTime: end}
+}
+
+func TestProject(t *testing.T) {
+	col := entity.Col{ID: "COL", Quantity: 100, MinQuantity: 90,
+		LatestDesiredDeliveryDate: due}
+
+	for _, c := range []struct {
+		name string
+		sos  []entity.SupplyOrder
+		want OnTime
+	}{
+		{"early", []entity.SupplyOrder{so(100, due.Add(-48*time.Hour))}, High},
+		{"just in time", []entity.SupplyOrder{so(100, due)}, Medium},
+		{"partial", []entity.SupplyOrder{so(50, due.Add(-48*time.Hour))}, Low},
+		{"late", []entity.SupplyOrder{so(100, due.Add(time.Hour))}, Low},
+		{"very late", []entity.SupplyOrder{so(100, due.Add(48*time.Hour))},
+			None},
+		{"unplanned", nil, None},
+	} {
+		got := Project(col, c.sos, DefaultMargin)
+		if got.OnTime != c.want {
+			t.Errorf("%s: on time = %s, want %s", c.name, got.OnTime, c.want)
+		}
+	}
+}
+
+func TestOnTimeRate(t *testing.T) {
+	col := entity.Col{ID: "COL", Name: "A", Quantity: 100,
+		LatestDesiredDeliveryDate: due}
+
+	cols := []Col{
+		Project(col, []entity.SupplyOrder{so(100, due.Add(-48*time.Hour))},
+			DefaultMargin),
+		Project(col, []entity.SupplyOrder{so(100, due)}, DefaultMargin),
+		// Partial and unplanned COLs aren't late but aren't on time either.
+		Project(col, []entity.SupplyOrder{so(50, due)}, DefaultMargin),
+		Project(col, nil, DefaultMargin),
+		Project(col, []entity.SupplyOrder{so(100, due.Add(time.Hour))},
+			DefaultMargin),
+	}
+
+	r := NewReport(cols)
+
+	if len(r.ByName) != 1 {
+		t.Fatalf("groups = %d, want 1", len(r.ByName))
+	}
+
+	g := r.ByName[0]
+
+	if g.Late != 1 {
+		t.Errorf("late = %d, want 1", g.Late)
+	}
+	if want := 2.0 / 5; g.OnTimeRate != want {
+		t.Errorf("on time rate = %v, want %v", g.OnTimeRate, want)
+	}
+}
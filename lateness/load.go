@@ -0,0 +1,34 @@
+package lateness
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+// Load projects lateness of all COLs.
+func Load(st storage.Storage, margin time.Duration) (Report, error) {
+	cols, err := st.Cols.List()
+	if err != nil {
+		return Report{}, fmt.Errorf("list COLs: %w", err)
+	}
+
+	sos, err := st.SupplyOrders.List()
+	if err != nil {
+		return Report{}, fmt.Errorf("list supply orders: %w", err)
+	}
+
+	byCol := map[string][]entity.SupplyOrder{}
+	for _, so := range sos {
+		byCol[so.ColID] = append(byCol[so.ColID], so)
+	}
+
+	var cs []Col
+	for _, col := range cols {
+		cs = append(cs, Project(col, byCol[col.ID], margin))
+	}
+
+	return NewReport(cs), nil
+}
@@ -1,24 +1,69 @@
 package main
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
-
-	"github.com/labstack/echo"
-	"github.com/labstack/echo/middleware"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
+
+	"github.com/dimuls/mipt-hack-accenture/api"
+	"github.com/dimuls/mipt-hack-accenture/dataset"
+	"github.com/dimuls/mipt-hack-accenture/memory"
+	"github.com/dimuls/mipt-hack-accenture/postgres"
+	"github.com/dimuls/mipt-hack-accenture/storage"
 )
 
 type config struct {
+	// Storage is either "postgres" (default) or "memory".
+	Storage     string `yaml:"storage"`
 	PostgresURI string `yaml:"postgres_uri"`
+	// DataPath is path to CSV files directory loaded to memory storage.
+	DataPath string `yaml:"data_path"`
+	// Timezone is time zone of CSV timestamps and of dates in API queries,
+	// for example Europe/Moscow. It is UTC by default.
+	Timezone string `yaml:"timezone"`
+	// TimeLayouts overrides layouts of CSV timestamps by table.column.
+	TimeLayouts map[string][]string `yaml:"time_layouts"`
+	BindAddr    string              `yaml:"bind_addr"`
+	// SkipMigrations disables applying of migrations on server start.
+	SkipMigrations bool `yaml:"skip_migrations"`
 }
 
+const (
+	defaultBindAddr = ":8080"
+	shutdownTimeout = 30 * time.Second
+)
+
+const usage = `usage: %s <config> [command]
+
+commands:
+  migrate up        apply all migrations
+  migrate down      revert last applied migration
+  migrate status    show migrations status
+  migrate to N      apply or revert migrations up to version N
+  migrate baseline N
+                    mark migrations up to version N as applied without
+                    running them, for databases created before migrations
+                    were tracked; version 1 is baselined automatically
+                    when plant table exists
+  schedule [flags] [supply order id...]
+                    schedule supply orders, all by default,
+                    run with -h to see flags
+  pegging <col id>  show supply orders, operations and resource groups
+                    of COL
+
+without command HTTP server is started`
+
 func main() {
-	if len(os.Args) != 2 {
-		logrus.Fatal("expected exact one argument: path to config file")
+	if len(os.Args) < 2 {
+		logrus.Fatalf(usage, os.Args[0])
 	}
 
 	cYAML, err := ioutil.ReadFile(os.Args[1])
@@ -33,18 +78,142 @@ func main() {
 		logrus.WithError(err).Fatal("failed to YAML unmarshal config")
 	}
 
+	if c.BindAddr == "" {
+		c.BindAddr = defaultBindAddr
+	}
+
+	args := os.Args[2:]
+
+	if len(args) == 0 {
+		serve(c)
+		return
+	}
+
+	switch args[0] {
+	case "migrate":
+		migrate(c, args[1:])
+	case "schedule":
+		schedule(c, args[1:])
+	case "pegging":
+		peg(c, args[1:])
+	default:
+		logrus.Fatalf(usage, os.Args[0])
+	}
+}
+
+func connectPostgres(c config) *sqlx.DB {
 	db, err := sqlx.Connect("postgres", c.PostgresURI)
 	if err != nil {
 		logrus.WithError(err).Fatal("failed to connect to postgres")
 	}
+	return db
+}
+
+func closePostgres(db *sqlx.DB) {
+	err := db.Close()
+	if err != nil {
+		logrus.WithError(err).Error("failed to close postgres")
+	}
+}
+
+// openStorage returns storage chosen by config and function to close it.
+func openStorage(c config) (storage.Storage, func()) {
+	var (
+		st  storage.Storage
+		err error
+	)
+
+	switch c.Storage {
+	case "", "postgres":
+		db := connectPostgres(c)
+
+		if !c.SkipMigrations {
+			m, err := postgres.NewMigrator(db)
+			if err != nil {
+				logrus.WithError(err).Fatal("failed to create migrator")
+			}
+
+			err = m.Up()
+			if err != nil {
+				logrus.WithError(err).Fatal("failed to apply migrations")
+			}
+		}
+
+		return postgres.NewStorage(db), func() { closePostgres(db) }
+
+	case "memory":
+		var dc dataset.Config
+
+		dc, err = dataset.NewConfig(c.Timezone, c.TimeLayouts)
+		if err != nil {
+			logrus.WithError(err).Fatal("failed to configure dataset")
+		}
+
+		st, err = memory.Load(c.DataPath, dc)
+		if err != nil {
+			logrus.WithError(err).Fatal("failed to load memory storage")
+		}
+
+		logrus.WithField("data_path", c.DataPath).Info(
+			"memory storage loaded")
+
+	default:
+		logrus.WithField("storage", c.Storage).Fatal("unknown storage")
+	}
+
+	return st, func() {}
+}
+
+// location returns time zone of config.
+func location(c config) *time.Location {
+	if c.Timezone == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to load time zone")
+	}
+
+	return loc
+}
+
+func serve(c config) {
+	st, closeStorage := openStorage(c)
+	defer closeStorage()
+
+	s := api.NewServer(st, location(c))
+
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+		sig := <-signals
 
-	e := echo.New()
-	e.Use(middleware.Recover())
-	e.Use(middleware.CORS())
+		logrus.WithField("signal", sig).Info("shutting down")
+
+		ctx, cancel := context.WithTimeout(context.Background(),
+			shutdownTimeout)
+		defer cancel()
+
+		err := s.Shutdown(ctx)
+		if err != nil {
+			logrus.WithError(err).Error("failed to gracefully shutdown server")
+		}
+	}()
+
+	logrus.WithField("bind_addr", c.BindAddr).Info("starting server")
+
+	err := s.Start(c.BindAddr)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to start server")
+	}
 
-	// TODO
-	//e.GET("/resource-group", func(c echo.Context) error {
-	//
-	//})
+	<-stopped
 
+	logrus.Info("server stopped")
 }
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+type ColRepo struct {
+	mx   sync.RWMutex
+	cols map[string]entity.Col
+
+	routings *RoutingRepo
+	products *ProductRepo
+}
+
+func NewColRepo(routings *RoutingRepo, products *ProductRepo) *ColRepo {
+	return &ColRepo{
+		routings: routings,
+		products: products,
+		cols:     map[string]entity.Col{},
+	}
+}
+
+func (r *ColRepo) List() ([]entity.Col, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	cs := make([]entity.Col, 0, len(r.cols))
+	for _, c := range r.cols {
+		cs = append(cs, c)
+	}
+
+	sort.Slice(cs, func(i, j int) bool {
+		return cs[i].ID < cs[j].ID
+	})
+
+	return cs, nil
+}
+
+func (r *ColRepo) Get(id string) (entity.Col, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	c, exists := r.cols[id]
+	if !exists {
+		return entity.Col{}, storage.ErrNotFound
+	}
+
+	return c, nil
+}
+
+func (r *ColRepo) Add(c entity.Col) error {
+	if !r.routings.exists(c.RoutingID) || !r.products.exists(c.ProductID) {
+		return storage.ErrBrokenReference
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if _, exists := r.cols[c.ID]; exists {
+		return storage.ErrAlreadyExists
+	}
+
+	r.cols[c.ID] = c
+
+	return nil
+}
+
+// exists returns true if COL with id exists.
+func (r *ColRepo) exists(id string) bool {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	_, exists := r.cols[id]
+
+	return exists
+}
@@ -0,0 +1,103 @@
+// Package memory implements storage repositories holding data in memory.
+// It is meant for demos and tests where PostgreSQL is not available.
+package memory
+
+import (
+	"fmt"
+
+	"github.com/dimuls/mipt-hack-accenture/dataset"
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+// NewStorage returns empty storage with all repositories held in memory.
+func NewStorage() storage.Storage {
+	var (
+		plants         = NewPlantRepo()
+		stockingPoints = NewStockingPointRepo()
+		products       = NewProductRepo()
+		resourceGroups = NewResourceGroupRepo(plants)
+		routings       = NewRoutingRepo(products, stockingPoints, plants,
+			resourceGroups)
+		cols         = NewColRepo(routings, products)
+		supplyOrders = NewSupplyOrderRepo(cols, routings, products,
+			stockingPoints)
+		operations = NewSupplyOrderOperationRepo(supplyOrders,
+			resourceGroups, routings)
+	)
+
+	// Repos check references as postgres checks foreign keys. Repos
+	// referencing resource groups check them before locking themselves, so
+	// resource groups may check those repos on remove without deadlock.
+	resourceGroups.routings = routings
+	resourceGroups.operations = operations
+
+	return storage.Storage{
+		Plants:                plants,
+		StockingPoints:        stockingPoints,
+		Products:              products,
+		ResourceGroups:        resourceGroups,
+		Routings:              routings,
+		Cols:                  cols,
+		SupplyOrders:          supplyOrders,
+		SupplyOrderOperations: operations,
+	}
+}
+
+// Load returns storage filled with CSV files from dataPath read with config
+// c.
+func Load(dataPath string, c dataset.Config) (storage.Storage, error) {
+	st := NewStorage()
+
+	adds := map[string]func(e interface{}) error{
+		"plant": func(e interface{}) error {
+			return st.Plants.Add(e.(entity.Plant))
+		},
+		"stocking_point": func(e interface{}) error {
+			return st.StockingPoints.Add(e.(entity.StockingPoint))
+		},
+		"resource_group": func(e interface{}) error {
+			return st.ResourceGroups.Add(e.(entity.ResourceGroup))
+		},
+		"resource": func(e interface{}) error {
+			return st.ResourceGroups.AddResource(e.(entity.Resource))
+		},
+		"product": func(e interface{}) error {
+			return st.Products.Add(e.(entity.Product))
+		},
+		"resource_group_period": func(e interface{}) error {
+			return st.ResourceGroups.AddPeriod(e.(entity.ResourceGroupPeriod))
+		},
+		"routing": func(e interface{}) error {
+			return st.Routings.Add(e.(entity.Routing))
+		},
+		"routing_step": func(e interface{}) error {
+			return st.Routings.AddStep(e.(entity.RoutingStep))
+		},
+		"col": func(e interface{}) error {
+			return st.Cols.Add(e.(entity.Col))
+		},
+		"supply_order": func(e interface{}) error {
+			return st.SupplyOrders.Add(e.(entity.SupplyOrder))
+		},
+		"supply_order_operation": func(e interface{}) error {
+			return st.SupplyOrderOperations.Add(
+				e.(entity.SupplyOrderOperation))
+		},
+	}
+
+	for _, t := range dataset.Tables {
+		add, exists := adds[t.Name]
+		if !exists {
+			return storage.Storage{}, fmt.Errorf(
+				"memory storage doesn't support %s table", t.Name)
+		}
+
+		err := t.Read(dataPath, c, add)
+		if err != nil {
+			return storage.Storage{}, fmt.Errorf("load %s: %w", t.Name, err)
+		}
+	}
+
+	return st, nil
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+// fixture returns storage with one row of every table, rows reference
+// each other.
+func fixture(t *testing.T) storage.Storage {
+	t.Helper()
+
+	st := NewStorage()
+
+	for _, add := range []func() error{
+		func() error { return st.Plants.Add(entity.Plant{ID: "P1"}) },
+		func() error {
+			return st.StockingPoints.Add(entity.StockingPoint{ID: "SP1"})
+		},
+		func() error { return st.Products.Add(entity.Product{ID: "PR1"}) },
+		func() error {
+			return st.ResourceGroups.Add(entity.ResourceGroup{ID: "RG1",
+				PlantID: "P1"})
+		},
+		func() error {
+			return st.Routings.Add(entity.Routing{ID: "RT1",
+				OutputProductID: "PR1", InputStockingPointID: "SP1",
+				OutputStockingPointID: "SP1"})
+		},
+		func() error {
+			return st.Routings.AddStep(entity.RoutingStep{ID: "RS1",
+				PlantID: "P1", RoutingID: "RT1", ResourceGroupID: "RG1"})
+		},
+		func() error {
+			return st.Cols.Add(entity.Col{ID: "COL1", RoutingID: "RT1",
+				ProductID: "PR1"})
+		},
+		func() error {
+			return st.SupplyOrders.Add(entity.SupplyOrder{ID: "SO1",
+				ColID: "COL1", RoutingID: "RT1", ProductID: "PR1",
+				StockingPointID: "SP1"})
+		},
+		func() error {
+			return st.SupplyOrderOperations.Add(entity.SupplyOrderOperation{
+				ID: "OP1", SupplyOrderID: "SO1", ResourceGroupID: "RG1",
+				RoutingStepID: "RS1"})
+		},
+	} {
+		err := add()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return st
+}
+
+func TestBrokenReferences(t *testing.T) {
+	st := fixture(t)
+
+	for name, add := range map[string]func() error{
+		"resource": func() error {
+			return st.ResourceGroups.AddResource(entity.Resource{ID: "R2",
+				ResourceGroupID: "RG2"})
+		},
+		"period": func() error {
+			return st.ResourceGroups.AddPeriod(entity.ResourceGroupPeriod{
+				ID: "RGP2", ResourceGroupID: "RG2"})
+		},
+		"routing": func() error {
+			return st.Routings.Add(entity.Routing{ID: "RT2",
+				OutputProductID: "PR1", InputStockingPointID: "SP2",
+				OutputStockingPointID: "SP1"})
+		},
+		"routing step": func() error {
+			return st.Routings.AddStep(entity.RoutingStep{ID: "RS2",
+				PlantID: "P1", RoutingID: "RT2", ResourceGroupID: "RG1"})
+		},
+		"col": func() error {
+			return st.Cols.Add(entity.Col{ID: "COL2", RoutingID: "RT1",
+				ProductID: "PR2"})
+		},
+		"supply order": func() error {
+			return st.SupplyOrders.Add(entity.SupplyOrder{ID: "SO2",
+				ColID: "COL2", RoutingID: "RT1", ProductID: "PR1",
+				StockingPointID: "SP1"})
+		},
+		"operation": func() error {
+			return st.SupplyOrderOperations.Add(entity.SupplyOrderOperation{
+				ID: "OP2", SupplyOrderID: "SO1", ResourceGroupID: "RG1",
+				RoutingStepID: "RS2"})
+		},
+	} {
+		err := add()
+		if !errors.Is(err, storage.ErrBrokenReference) {
+			t.Errorf("%s: got %v, want %v", name, err,
+				storage.ErrBrokenReference)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+type PlantRepo struct {
+	mx     sync.RWMutex
+	plants map[string]entity.Plant
+}
+
+func NewPlantRepo() *PlantRepo {
+	return &PlantRepo{plants: map[string]entity.Plant{}}
+}
+
+func (r *PlantRepo) List() ([]entity.Plant, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	ps := make([]entity.Plant, 0, len(r.plants))
+	for _, p := range r.plants {
+		ps = append(ps, p)
+	}
+
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].ID < ps[j].ID
+	})
+
+	return ps, nil
+}
+
+func (r *PlantRepo) Get(id string) (entity.Plant, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	p, exists := r.plants[id]
+	if !exists {
+		return entity.Plant{}, storage.ErrNotFound
+	}
+
+	return p, nil
+}
+
+func (r *PlantRepo) Add(p entity.Plant) error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if _, exists := r.plants[p.ID]; exists {
+		return storage.ErrAlreadyExists
+	}
+
+	r.plants[p.ID] = p
+
+	return nil
+}
+
+// exists returns true if plant with id exists.
+func (r *PlantRepo) exists(id string) bool {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	_, exists := r.plants[id]
+
+	return exists
+}
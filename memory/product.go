@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+type ProductRepo struct {
+	mx       sync.RWMutex
+	products map[string]entity.Product
+}
+
+func NewProductRepo() *ProductRepo {
+	return &ProductRepo{products: map[string]entity.Product{}}
+}
+
+func (r *ProductRepo) List() ([]entity.Product, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	ps := make([]entity.Product, 0, len(r.products))
+	for _, p := range r.products {
+		ps = append(ps, p)
+	}
+
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].ID < ps[j].ID
+	})
+
+	return ps, nil
+}
+
+func (r *ProductRepo) Get(id string) (entity.Product, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	p, exists := r.products[id]
+	if !exists {
+		return entity.Product{}, storage.ErrNotFound
+	}
+
+	return p, nil
+}
+
+func (r *ProductRepo) Add(p entity.Product) error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if _, exists := r.products[p.ID]; exists {
+		return storage.ErrAlreadyExists
+	}
+
+	r.products[p.ID] = p
+
+	return nil
+}
+
+// exists returns true if product with id exists.
+func (r *ProductRepo) exists(id string) bool {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	_, exists := r.products[id]
+
+	return exists
+}
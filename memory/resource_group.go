@@ -0,0 +1,296 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+type ResourceGroupRepo struct {
+	mx             sync.RWMutex
+	resourceGroups map[string]entity.ResourceGroup
+	resources      map[string]entity.Resource
+	periods        map[string]entity.ResourceGroupPeriod
+
+	// Repos referenced by or referencing resource groups are checked as
+	// postgres checks foreign keys. Referencing repos are set by NewStorage
+	// since they reference resource groups too.
+	plants     *PlantRepo
+	routings   *RoutingRepo
+	operations *SupplyOrderOperationRepo
+}
+
+func NewResourceGroupRepo(plants *PlantRepo) *ResourceGroupRepo {
+	return &ResourceGroupRepo{
+		plants:         plants,
+		resourceGroups: map[string]entity.ResourceGroup{},
+		resources:      map[string]entity.Resource{},
+		periods:        map[string]entity.ResourceGroupPeriod{},
+	}
+}
+
+func (r *ResourceGroupRepo) List() ([]entity.ResourceGroup, error) {
+	return r.list(func(entity.ResourceGroup) bool { return true }, false), nil
+}
+
+func (r *ResourceGroupRepo) ListWithPeriods() ([]entity.ResourceGroup, error) {
+	return r.list(func(entity.ResourceGroup) bool { return true }, true), nil
+}
+
+func (r *ResourceGroupRepo) ByPlant(plantID string) (
+	[]entity.ResourceGroup, error) {
+
+	return r.list(func(rg entity.ResourceGroup) bool {
+		return rg.PlantID == plantID
+	}, false), nil
+}
+
+// list returns resource groups matching filter with their resources and, if
+// periods is true, with their periods.
+func (r *ResourceGroupRepo) list(filter func(entity.ResourceGroup) bool,
+	periods bool) []entity.ResourceGroup {
+
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	rgResources := map[string][]entity.Resource{}
+	for _, res := range r.resources {
+		rgResources[res.ResourceGroupID] = append(
+			rgResources[res.ResourceGroupID], res)
+	}
+
+	rgs := []entity.ResourceGroup{}
+	for _, rg := range r.resourceGroups {
+		if !filter(rg) {
+			continue
+		}
+		rg.Resources = rgResources[rg.ID]
+		sort.Slice(rg.Resources, func(i, j int) bool {
+			return rg.Resources[i].ID < rg.Resources[j].ID
+		})
+		if periods {
+			rg.Periods = r.groupPeriods(rg.ID)
+		}
+		rgs = append(rgs, rg)
+	}
+
+	sort.Slice(rgs, func(i, j int) bool {
+		return rgs[i].ID < rgs[j].ID
+	})
+
+	return rgs
+}
+
+func (r *ResourceGroupRepo) Get(id string) (entity.ResourceGroup, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	rg, exists := r.resourceGroups[id]
+	if !exists {
+		return entity.ResourceGroup{}, storage.ErrNotFound
+	}
+
+	rg.Resources = r.groupResources(id)
+	rg.Periods = r.groupPeriods(id)
+
+	return rg, nil
+}
+
+func (r *ResourceGroupRepo) Add(rg entity.ResourceGroup) error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if _, exists := r.resourceGroups[rg.ID]; exists {
+		return storage.ErrAlreadyExists
+	}
+
+	if !r.plants.exists(rg.PlantID) {
+		return storage.ErrBrokenReference
+	}
+
+	err := r.addChildren(rg)
+	if err != nil {
+		return err
+	}
+
+	r.resourceGroups[rg.ID] = entity.ResourceGroup{ID: rg.ID,
+		PlantID: rg.PlantID, Name: rg.Name}
+
+	return nil
+}
+
+func (r *ResourceGroupRepo) Update(rg entity.ResourceGroup) error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if _, exists := r.resourceGroups[rg.ID]; !exists {
+		return storage.ErrNotFound
+	}
+
+	if !r.plants.exists(rg.PlantID) {
+		return storage.ErrBrokenReference
+	}
+
+	removedResources, removedPeriods := r.removeChildren(rg.ID)
+
+	err := r.addChildren(rg)
+	if err != nil {
+		// Restore removed children as postgres would rollback transaction.
+		for _, res := range removedResources {
+			r.resources[res.ID] = res
+		}
+		for _, p := range removedPeriods {
+			r.periods[p.ID] = p
+		}
+		return err
+	}
+
+	r.resourceGroups[rg.ID] = entity.ResourceGroup{ID: rg.ID,
+		PlantID: rg.PlantID, Name: rg.Name}
+
+	return nil
+}
+
+func (r *ResourceGroupRepo) Remove(id string) error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if _, exists := r.resourceGroups[id]; !exists {
+		return storage.ErrNotFound
+	}
+
+	if r.routings.usesResourceGroup(id) || r.operations.usesResourceGroup(id) {
+		return storage.ErrBrokenReference
+	}
+
+	r.removeChildren(id)
+
+	delete(r.resourceGroups, id)
+
+	return nil
+}
+
+func (r *ResourceGroupRepo) AddResource(res entity.Resource) error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if _, exists := r.resources[res.ID]; exists {
+		return storage.ErrAlreadyExists
+	}
+
+	if _, exists := r.resourceGroups[res.ResourceGroupID]; !exists {
+		return storage.ErrBrokenReference
+	}
+
+	r.resources[res.ID] = res
+
+	return nil
+}
+
+func (r *ResourceGroupRepo) AddPeriod(p entity.ResourceGroupPeriod) error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if _, exists := r.periods[p.ID]; exists {
+		return storage.ErrAlreadyExists
+	}
+
+	if _, exists := r.resourceGroups[p.ResourceGroupID]; !exists {
+		return storage.ErrBrokenReference
+	}
+
+	r.periods[p.ID] = p
+
+	return nil
+}
+
+// addChildren adds all resources and periods of rg or none of them.
+func (r *ResourceGroupRepo) addChildren(rg entity.ResourceGroup) error {
+	resourceIDs := map[string]bool{}
+	for _, res := range rg.Resources {
+		if _, exists := r.resources[res.ID]; exists || resourceIDs[res.ID] {
+			return storage.ErrAlreadyExists
+		}
+		resourceIDs[res.ID] = true
+	}
+
+	periodIDs := map[string]bool{}
+	for _, p := range rg.Periods {
+		if _, exists := r.periods[p.ID]; exists || periodIDs[p.ID] {
+			return storage.ErrAlreadyExists
+		}
+		periodIDs[p.ID] = true
+	}
+
+	for _, res := range rg.Resources {
+		res.ResourceGroupID = rg.ID
+		r.resources[res.ID] = res
+	}
+
+	for _, p := range rg.Periods {
+		p.ResourceGroupID = rg.ID
+		r.periods[p.ID] = p
+	}
+
+	return nil
+}
+
+func (r *ResourceGroupRepo) removeChildren(id string) (
+	[]entity.Resource, []entity.ResourceGroupPeriod) {
+
+	rs := r.groupResources(id)
+	for _, res := range rs {
+		delete(r.resources, res.ID)
+	}
+
+	ps := r.groupPeriods(id)
+	for _, p := range ps {
+		delete(r.periods, p.ID)
+	}
+
+	return rs, ps
+}
+
+func (r *ResourceGroupRepo) groupResources(id string) []entity.Resource {
+	var rs []entity.Resource
+	for _, res := range r.resources {
+		if res.ResourceGroupID == id {
+			rs = append(rs, res)
+		}
+	}
+
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].ID < rs[j].ID
+	})
+
+	return rs
+}
+
+func (r *ResourceGroupRepo) groupPeriods(
+	id string) []entity.ResourceGroupPeriod {
+
+	var ps []entity.ResourceGroupPeriod
+	for _, p := range r.periods {
+		if p.ResourceGroupID == id {
+			ps = append(ps, p)
+		}
+	}
+
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].StartDate.Before(ps[j].StartDate)
+	})
+
+	return ps
+}
+
+// exists returns true if resource group with id exists.
+func (r *ResourceGroupRepo) exists(id string) bool {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	_, exists := r.resourceGroups[id]
+
+	return exists
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+func TestResourceGroupReferences(t *testing.T) {
+	st := fixture(t)
+
+	err := st.ResourceGroups.Add(entity.ResourceGroup{ID: "RG2",
+		PlantID: "P2"})
+	if !errors.Is(err, storage.ErrBrokenReference) {
+		t.Errorf("add with unknown plant: got %v, want %v", err,
+			storage.ErrBrokenReference)
+	}
+
+	for _, id := range []string{"RG2", "RG3"} {
+		err = st.ResourceGroups.Add(entity.ResourceGroup{ID: id,
+			PlantID: "P1", Resources: []entity.Resource{{ID: "R" + id}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = st.ResourceGroups.Update(entity.ResourceGroup{ID: "RG2",
+		PlantID: "P2"})
+	if !errors.Is(err, storage.ErrBrokenReference) {
+		t.Errorf("update with unknown plant: got %v, want %v", err,
+			storage.ErrBrokenReference)
+	}
+
+	rg, err := st.ResourceGroups.Get("RG2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rg.PlantID != "P1" || len(rg.Resources) != 1 {
+		t.Errorf("failed update changed resource group: %+v", rg)
+	}
+
+	// RG1 is used by routing step and RG2 by operation.
+	err = st.SupplyOrderOperations.Add(entity.SupplyOrderOperation{
+		ID: "OP2", SupplyOrderID: "SO1", ResourceGroupID: "RG2",
+		RoutingStepID: "RS1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"RG1", "RG2"} {
+		err = st.ResourceGroups.Remove(id)
+		if !errors.Is(err, storage.ErrBrokenReference) {
+			t.Errorf("remove used %s: got %v, want %v", id, err,
+				storage.ErrBrokenReference)
+		}
+
+		_, err = st.ResourceGroups.Get(id)
+		if err != nil {
+			t.Errorf("failed remove removed %s: %v", id, err)
+		}
+	}
+
+	err = st.ResourceGroups.Remove("RG3")
+	if err != nil {
+		t.Errorf("remove unused: %v", err)
+	}
+}
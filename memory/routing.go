@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+type RoutingRepo struct {
+	mx        sync.RWMutex
+	routings  map[string]entity.Routing
+	steps     map[string]entity.RoutingStep
+	byRouting map[string][]string
+
+	products       *ProductRepo
+	stockingPoints *StockingPointRepo
+	plants         *PlantRepo
+	resourceGroups *ResourceGroupRepo
+}
+
+func NewRoutingRepo(products *ProductRepo,
+	stockingPoints *StockingPointRepo, plants *PlantRepo,
+	resourceGroups *ResourceGroupRepo) *RoutingRepo {
+
+	return &RoutingRepo{
+		products:       products,
+		stockingPoints: stockingPoints,
+		plants:         plants,
+		resourceGroups: resourceGroups,
+		routings:       map[string]entity.Routing{},
+		steps:          map[string]entity.RoutingStep{},
+		byRouting:      map[string][]string{},
+	}
+}
+
+func (r *RoutingRepo) List() ([]entity.Routing, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	rs := make([]entity.Routing, 0, len(r.routings))
+	for _, rt := range r.routings {
+		rs = append(rs, rt)
+	}
+
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].ID < rs[j].ID
+	})
+
+	return rs, nil
+}
+
+func (r *RoutingRepo) Get(id string) (entity.Routing, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	rt, exists := r.routings[id]
+	if !exists {
+		return entity.Routing{}, storage.ErrNotFound
+	}
+
+	return rt, nil
+}
+
+func (r *RoutingRepo) Add(rt entity.Routing) error {
+	// Input product has no foreign key in postgres.
+	if !r.products.exists(rt.OutputProductID) ||
+		!r.stockingPoints.exists(rt.InputStockingPointID) ||
+		!r.stockingPoints.exists(rt.OutputStockingPointID) {
+		return storage.ErrBrokenReference
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if _, exists := r.routings[rt.ID]; exists {
+		return storage.ErrAlreadyExists
+	}
+
+	r.routings[rt.ID] = rt
+
+	return nil
+}
+
+func (r *RoutingRepo) Steps(routingID string) ([]entity.RoutingStep, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	var rss []entity.RoutingStep
+	for _, id := range r.byRouting[routingID] {
+		rss = append(rss, r.steps[id])
+	}
+
+	sort.Slice(rss, func(i, j int) bool {
+		return rss[i].SequenceNumber < rss[j].SequenceNumber
+	})
+
+	return rss, nil
+}
+
+func (r *RoutingRepo) AllSteps() ([]entity.RoutingStep, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	rss := make([]entity.RoutingStep, 0, len(r.steps))
+	for _, rs := range r.steps {
+		rss = append(rss, rs)
+	}
+
+	sort.Slice(rss, func(i, j int) bool {
+		if rss[i].RoutingID != rss[j].RoutingID {
+			return rss[i].RoutingID < rss[j].RoutingID
+		}
+		return rss[i].SequenceNumber < rss[j].SequenceNumber
+	})
+
+	return rss, nil
+}
+
+func (r *RoutingRepo) AddStep(rs entity.RoutingStep) error {
+	if !r.plants.exists(rs.PlantID) ||
+		!r.resourceGroups.exists(rs.ResourceGroupID) {
+		return storage.ErrBrokenReference
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if _, exists := r.steps[rs.ID]; exists {
+		return storage.ErrAlreadyExists
+	}
+
+	if _, exists := r.routings[rs.RoutingID]; !exists {
+		return storage.ErrBrokenReference
+	}
+
+	r.steps[rs.ID] = rs
+	r.byRouting[rs.RoutingID] = append(r.byRouting[rs.RoutingID], rs.ID)
+
+	return nil
+}
+
+// usesResourceGroup returns true if any routing step uses resource group.
+func (r *RoutingRepo) usesResourceGroup(id string) bool {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	for _, s := range r.steps {
+		if s.ResourceGroupID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// exists returns true if routing with id exists.
+func (r *RoutingRepo) exists(id string) bool {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	_, exists := r.routings[id]
+
+	return exists
+}
+
+// stepExists returns true if routing step with id exists.
+func (r *RoutingRepo) stepExists(id string) bool {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	_, exists := r.steps[id]
+
+	return exists
+}
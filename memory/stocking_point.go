@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+type StockingPointRepo struct {
+	mx             sync.RWMutex
+	stockingPoints map[string]entity.StockingPoint
+}
+
+func NewStockingPointRepo() *StockingPointRepo {
+	return &StockingPointRepo{
+		stockingPoints: map[string]entity.StockingPoint{},
+	}
+}
+
+func (r *StockingPointRepo) List() ([]entity.StockingPoint, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	sps := make([]entity.StockingPoint, 0, len(r.stockingPoints))
+	for _, sp := range r.stockingPoints {
+		sps = append(sps, sp)
+	}
+
+	sort.Slice(sps, func(i, j int) bool {
+		return sps[i].ID < sps[j].ID
+	})
+
+	return sps, nil
+}
+
+func (r *StockingPointRepo) Get(id string) (entity.StockingPoint, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	sp, exists := r.stockingPoints[id]
+	if !exists {
+		return entity.StockingPoint{}, storage.ErrNotFound
+	}
+
+	return sp, nil
+}
+
+func (r *StockingPointRepo) Add(sp entity.StockingPoint) error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if _, exists := r.stockingPoints[sp.ID]; exists {
+		return storage.ErrAlreadyExists
+	}
+
+	r.stockingPoints[sp.ID] = sp
+
+	return nil
+}
+
+// exists returns true if stocking point with id exists.
+func (r *StockingPointRepo) exists(id string) bool {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	_, exists := r.stockingPoints[id]
+
+	return exists
+}
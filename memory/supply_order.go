@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+type SupplyOrderRepo struct {
+	mx           sync.RWMutex
+	supplyOrders map[string]entity.SupplyOrder
+	byCol        map[string][]string
+
+	cols           *ColRepo
+	routings       *RoutingRepo
+	products       *ProductRepo
+	stockingPoints *StockingPointRepo
+}
+
+func NewSupplyOrderRepo(cols *ColRepo, routings *RoutingRepo,
+	products *ProductRepo,
+	stockingPoints *StockingPointRepo) *SupplyOrderRepo {
+
+	return &SupplyOrderRepo{
+		cols:           cols,
+		routings:       routings,
+		products:       products,
+		stockingPoints: stockingPoints,
+		supplyOrders:   map[string]entity.SupplyOrder{},
+		byCol:          map[string][]string{},
+	}
+}
+
+func (r *SupplyOrderRepo) List() ([]entity.SupplyOrder, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	sos := make([]entity.SupplyOrder, 0, len(r.supplyOrders))
+	for _, so := range r.supplyOrders {
+		sos = append(sos, so)
+	}
+
+	sort.Slice(sos, func(i, j int) bool {
+		return sos[i].ID < sos[j].ID
+	})
+
+	return sos, nil
+}
+
+func (r *SupplyOrderRepo) Get(id string) (entity.SupplyOrder, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	so, exists := r.supplyOrders[id]
+	if !exists {
+		return entity.SupplyOrder{}, storage.ErrNotFound
+	}
+
+	return so, nil
+}
+
+func (r *SupplyOrderRepo) ByCol(colID string) ([]entity.SupplyOrder, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	var sos []entity.SupplyOrder
+	for _, id := range r.byCol[colID] {
+		sos = append(sos, r.supplyOrders[id])
+	}
+
+	sort.Slice(sos, func(i, j int) bool {
+		if sos[i].OrderPosition != sos[j].OrderPosition {
+			return sos[i].OrderPosition < sos[j].OrderPosition
+		}
+		return sos[i].ID < sos[j].ID
+	})
+
+	return sos, nil
+}
+
+func (r *SupplyOrderRepo) Add(so entity.SupplyOrder) error {
+	if !r.cols.exists(so.ColID) || !r.routings.exists(so.RoutingID) ||
+		!r.products.exists(so.ProductID) ||
+		!r.stockingPoints.exists(so.StockingPointID) {
+		return storage.ErrBrokenReference
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if _, exists := r.supplyOrders[so.ID]; exists {
+		return storage.ErrAlreadyExists
+	}
+
+	r.supplyOrders[so.ID] = so
+	r.byCol[so.ColID] = append(r.byCol[so.ColID], so.ID)
+
+	return nil
+}
+
+// setTimes sets start and end times of supply order if it exists.
+func (r *SupplyOrderRepo) setTimes(id string, start, end time.Time) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	so, exists := r.supplyOrders[id]
+	if !exists {
+		return
+	}
+
+	so.StartTime, so.EndTime = start, end
+	r.supplyOrders[id] = so
+}
+
+// exists returns true if supply order with id exists.
+func (r *SupplyOrderRepo) exists(id string) bool {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	_, exists := r.supplyOrders[id]
+
+	return exists
+}
@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+type SupplyOrderOperationRepo struct {
+	mx              sync.RWMutex
+	operations      map[string]entity.SupplyOrderOperation
+	bySupplyOrderID map[string][]string
+	// supplyOrders are supply orders which times follow their operations.
+	supplyOrders   *SupplyOrderRepo
+	resourceGroups *ResourceGroupRepo
+	routings       *RoutingRepo
+}
+
+func NewSupplyOrderOperationRepo(supplyOrders *SupplyOrderRepo,
+	resourceGroups *ResourceGroupRepo,
+	routings *RoutingRepo) *SupplyOrderOperationRepo {
+
+	return &SupplyOrderOperationRepo{
+		supplyOrders:    supplyOrders,
+		resourceGroups:  resourceGroups,
+		routings:        routings,
+		operations:      map[string]entity.SupplyOrderOperation{},
+		bySupplyOrderID: map[string][]string{},
+	}
+}
+
+func (r *SupplyOrderOperationRepo) Get(id string) (
+	entity.SupplyOrderOperation, error) {
+
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	op, exists := r.operations[id]
+	if !exists {
+		return entity.SupplyOrderOperation{}, storage.ErrNotFound
+	}
+
+	return op, nil
+}
+
+func (r *SupplyOrderOperationRepo) BySupplyOrder(supplyOrderID string) (
+	[]entity.SupplyOrderOperation, error) {
+
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	var ops []entity.SupplyOrderOperation
+	for _, id := range r.bySupplyOrderID[supplyOrderID] {
+		ops = append(ops, r.operations[id])
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].SequenceNumber < ops[j].SequenceNumber
+	})
+
+	return ops, nil
+}
+
+func (r *SupplyOrderOperationRepo) ByResourceGroup(resourceGroupID string,
+	from, to time.Time) ([]entity.SupplyOrderOperation, error) {
+
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	var ops []entity.SupplyOrderOperation
+	for _, op := range r.operations {
+		if op.ResourceGroupID == resourceGroupID &&
+			op.StartTime.Before(to) && op.EndTime.After(from) {
+			ops = append(ops, op)
+		}
+	}
+
+	sortByStartTime(ops)
+
+	return ops, nil
+}
+
+// sortByStartTime sorts operations by start time and then by id.
+func sortByStartTime(ops []entity.SupplyOrderOperation) {
+	sort.Slice(ops, func(i, j int) bool {
+		if !ops[i].StartTime.Equal(ops[j].StartTime) {
+			return ops[i].StartTime.Before(ops[j].StartTime)
+		}
+		return ops[i].ID < ops[j].ID
+	})
+}
+
+func (r *SupplyOrderOperationRepo) Add(op entity.SupplyOrderOperation) error {
+	if !r.supplyOrders.exists(op.SupplyOrderID) ||
+		!r.resourceGroups.exists(op.ResourceGroupID) ||
+		!r.routings.stepExists(op.RoutingStepID) {
+		return storage.ErrBrokenReference
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if _, exists := r.operations[op.ID]; exists {
+		return storage.ErrAlreadyExists
+	}
+
+	r.operations[op.ID] = op
+	r.bySupplyOrderID[op.SupplyOrderID] = append(
+		r.bySupplyOrderID[op.SupplyOrderID], op.ID)
+
+	return nil
+}
+
+func (r *SupplyOrderOperationRepo) SetTimes(
+	ops []entity.SupplyOrderOperation) error {
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	for _, op := range ops {
+		if _, exists := r.operations[op.ID]; !exists {
+			return storage.ErrNotFound
+		}
+	}
+
+	soIDs := map[string]bool{}
+
+	for _, op := range ops {
+		o := r.operations[op.ID]
+		o.StartTime, o.EndTime = op.StartTime, op.EndTime
+		r.operations[op.ID] = o
+		soIDs[o.SupplyOrderID] = true
+	}
+
+	for soID := range soIDs {
+		var start, end time.Time
+		for i, id := range r.bySupplyOrderID[soID] {
+			o := r.operations[id]
+			if i == 0 || o.StartTime.Before(start) {
+				start = o.StartTime
+			}
+			if i == 0 || o.EndTime.After(end) {
+				end = o.EndTime
+			}
+		}
+		r.supplyOrders.setTimes(soID, start, end)
+	}
+
+	return nil
+}
+
+// usesResourceGroup returns true if any operation uses resource group.
+func (r *SupplyOrderOperationRepo) usesResourceGroup(id string) bool {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	for _, op := range r.operations {
+		if op.ResourceGroupID == id {
+			return true
+		}
+	}
+
+	return false
+}
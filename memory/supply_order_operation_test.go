@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+func TestByResourceGroup(t *testing.T) {
+	st := fixture(t)
+
+	at := func(h int) time.Time {
+		return time.Date(2020, 1, 1, h, 0, 0, 0, time.UTC)
+	}
+
+	for id, window := range map[string][2]int{
+		"ENDS_AT_FROM":  {0, 2},
+		"OVERLAPS_FROM": {1, 3},
+		"INSIDE":        {3, 4},
+		"OVERLAPS_TO":   {5, 7},
+		"STARTS_AT_TO":  {6, 8},
+	} {
+		err := st.SupplyOrderOperations.Add(entity.SupplyOrderOperation{
+			ID: id, SupplyOrderID: "SO1", ResourceGroupID: "RG1",
+			RoutingStepID: "RS1", StartTime: at(window[0]),
+			EndTime: at(window[1])})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ops, err := st.SupplyOrderOperations.ByResourceGroup("RG1", at(2), at(6))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, op := range ops {
+		ids = append(ids, op.ID)
+	}
+
+	// OP1 of fixture has zero times, so it ends before window.
+	want := []string{"OVERLAPS_FROM", "INSIDE", "OVERLAPS_TO"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("operations = %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dimuls/mipt-hack-accenture/postgres"
+)
+
+func migrate(c config, args []string) {
+	if len(args) == 0 {
+		logrus.Fatalf(usage, os.Args[0])
+	}
+
+	db := connectPostgres(c)
+	defer closePostgres(db)
+
+	m, err := postgres.NewMigrator(db)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to create migrator")
+	}
+
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		err = m.Up()
+
+	case args[0] == "down" && len(args) == 1:
+		err = m.Down()
+
+	case args[0] == "to" && len(args) == 2:
+		var version int
+		version, err = strconv.Atoi(args[1])
+		if err != nil {
+			logrus.WithError(err).Fatal("failed to parse migration version")
+		}
+		err = m.To(version)
+
+	case args[0] == "baseline" && len(args) == 2:
+		var version int
+		version, err = strconv.Atoi(args[1])
+		if err != nil {
+			logrus.WithError(err).Fatal("failed to parse migration version")
+		}
+		err = m.Baseline(version)
+
+	case args[0] == "status" && len(args) == 1:
+		var ss []postgres.MigrationStatus
+		ss, err = m.Status()
+		if err != nil {
+			break
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range ss {
+			appliedAt := "not applied"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.String()
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		err = w.Flush()
+
+	default:
+		logrus.Fatalf(usage, os.Args[0])
+	}
+
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to migrate")
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/pegging"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+func peg(c config, args []string) {
+	if len(args) != 1 {
+		logrus.Fatal("pegging requires COL id")
+	}
+
+	st, closeStorage := openStorage(c)
+	defer closeStorage()
+
+	col, err := st.Cols.Get(args[0])
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			logrus.WithField("col_id", args[0]).Fatal("COL not found")
+		}
+		logrus.WithError(err).Fatal("failed to get COL")
+	}
+
+	p, err := pegging.Load(st, col)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to load pegging")
+	}
+
+	printPegging(p)
+}
+
+// printPegging prints pegging tree to stdout.
+func printPegging(p pegging.Col) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(w, "ID\tRESOURCE GROUP\tRESOURCES\tSTART\tEND\tDEADLINE\tSLACK")
+	fmt.Fprintf(w, "COL %s\t\t\t\t\t%s\t\n", p.ID,
+		formatTime(p.LatestDesiredDeliveryDate))
+
+	for _, so := range p.SupplyOrders {
+		fmt.Fprintf(w, "  supply order %s\t\t\t%s\t%s\t%s\t%s\n", so.ID,
+			formatTime(so.StartTime), formatTime(so.End),
+			formatTime(so.DeadlineTime), formatSlack(so.Slack))
+
+		for _, op := range so.Operations {
+			var rg, rs string
+			if op.ResourceGroup != nil {
+				rg = op.ResourceGroup.Name
+				var names []string
+				for _, r := range op.ResourceGroup.Resources {
+					names = append(names, r.ShortName)
+				}
+				rs = strings.Join(names, ", ")
+			}
+
+			fmt.Fprintf(w, "    %d operation %s\t%s\t%s\t%s\t%s\t\t%s\n",
+				op.SequenceNumber, op.ID, rg, rs, formatTime(op.StartTime),
+				formatTime(op.EndTime), formatSlack(op.Slack))
+		}
+	}
+
+	err := w.Flush()
+	if err != nil {
+		logrus.WithError(err).Error("failed to print pegging")
+	}
+}
+
+func formatSlack(d *entity.Duration) string {
+	if d == nil {
+		return "-"
+	}
+	return d.String()
+}
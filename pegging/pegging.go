@@ -0,0 +1,118 @@
+// Package pegging links COL with its supply orders, their operations and
+// resource groups of operations.
+package pegging
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+// Col is COL with its supply orders.
+type Col struct {
+	entity.Col
+	SupplyOrders []SupplyOrder `json:"supply_orders"`
+}
+
+// SupplyOrder is supply order with its operations ordered by sequence
+// number.
+type SupplyOrder struct {
+	entity.SupplyOrder
+	// End is end time of the last operation or of supply order if it has no
+	// operations.
+	End time.Time `json:"end"`
+	// Slack is time from End to deadline, it is negative if supply order
+	// is late and nil if there is no deadline.
+	Slack      *entity.Duration `json:"slack"`
+	Operations []Operation      `json:"operations"`
+}
+
+// Operation is supply order operation with its resource group.
+type Operation struct {
+	entity.SupplyOrderOperation
+	// Slack is time from operation end to deadline of supply order.
+	Slack *entity.Duration `json:"slack"`
+	// ResourceGroup is resource group with its resources, it is nil if
+	// resource group doesn't exist.
+	ResourceGroup *entity.ResourceGroup `json:"resource_group"`
+}
+
+// Load loads pegging of COL.
+func Load(st storage.Storage, col entity.Col) (Col, error) {
+	p := Col{Col: col, SupplyOrders: []SupplyOrder{}}
+
+	sos, err := st.SupplyOrders.ByCol(col.ID)
+	if err != nil {
+		return Col{}, fmt.Errorf("list supply orders: %w", err)
+	}
+
+	rgs := map[string]*entity.ResourceGroup{}
+
+	for _, so := range sos {
+		ops, err := st.SupplyOrderOperations.BySupplyOrder(so.ID)
+		if err != nil {
+			return Col{}, fmt.Errorf("list operations of supply order "+
+				"%s: %w", so.ID, err)
+		}
+
+		pso := SupplyOrder{
+			SupplyOrder: so,
+			End:         so.EndTime,
+			Operations:  []Operation{},
+		}
+
+		for _, op := range ops {
+			rg, loaded := rgs[op.ResourceGroupID]
+			if !loaded {
+				rg, err = resourceGroup(st, op.ResourceGroupID)
+				if err != nil {
+					return Col{}, err
+				}
+				rgs[op.ResourceGroupID] = rg
+			}
+
+			pso.Operations = append(pso.Operations, Operation{
+				SupplyOrderOperation: op,
+				Slack:                slack(op.EndTime, so.DeadlineTime),
+				ResourceGroup:        rg,
+			})
+
+			pso.End = op.EndTime
+		}
+
+		pso.Slack = slack(pso.End, so.DeadlineTime)
+
+		p.SupplyOrders = append(p.SupplyOrders, pso)
+	}
+
+	return p, nil
+}
+
+// resourceGroup returns resource group with resources but without periods,
+// it returns nil if resource group doesn't exist.
+func resourceGroup(st storage.Storage, id string) (
+	*entity.ResourceGroup, error) {
+
+	rg, err := st.ResourceGroups.Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get resource group %s: %w", id, err)
+	}
+
+	rg.Periods = nil
+
+	return &rg, nil
+}
+
+func slack(end, deadline time.Time) *entity.Duration {
+	if deadline.IsZero() || end.IsZero() {
+		return nil
+	}
+	s := entity.Duration(deadline.Sub(end))
+	return &s
+}
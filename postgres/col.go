@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+const colColumns = `
+	id,
+	routing_id,
+	product_id,
+	quantity,
+	min_quantity,
+	max_quantity,
+	has_sales_budget_reservation,
+	requires_order_combination,
+	number_of_active_routing_chain_upstream,
+	selected_shipping_shop,
+	result_product_type,
+	delivery_type,
+	planned_status,
+	name,
+	product_name,
+	latest_desired_delivery_date,
+	product_specification_id,
+	resource_group_ids
+`
+
+type ColRepo struct {
+	db *sqlx.DB
+}
+
+func NewColRepo(db *sqlx.DB) *ColRepo {
+	return &ColRepo{db: db}
+}
+
+func (r *ColRepo) List() ([]entity.Col, error) {
+	rows, err := r.db.Query(`select ` + colColumns + ` from col order by id`)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var cs []entity.Col
+
+	for rows.Next() {
+		c, err := scanCol(rows)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+
+	return cs, rows.Err()
+}
+
+func (r *ColRepo) Get(id string) (entity.Col, error) {
+	c, err := scanCol(r.db.QueryRow(
+		`select `+colColumns+` from col where id = $1`, id))
+	return c, storageErr(err)
+}
+
+func (r *ColRepo) Add(c entity.Col) error {
+	_, err := r.db.Exec(`
+		insert into col (`+colColumns+`)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
+			$14, $15, $16, $17, $18)
+	`, c.ID, c.RoutingID, c.ProductID, c.Quantity, c.MinQuantity,
+		c.MaxQuantity, c.HasSalesBudgetReservation, c.RequiresOrderCombination,
+		c.NumberOfActiveRoutingChainUpstream, c.SelectedShippingShop,
+		c.ResultProductType, c.DeliveryType, c.PlannedStatus, c.Name,
+		c.ProductName, c.LatestDesiredDeliveryDate, c.ProductSpecificationID,
+		pq.Array(c.ResourceGroupIDs))
+	return storageErr(err)
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanCol scans COL row selected with colColumns. It is needed since sqlx
+// can't scan text[] to []string.
+func scanCol(s scanner) (c entity.Col, err error) {
+	err = s.Scan(&c.ID, &c.RoutingID, &c.ProductID, &c.Quantity,
+		&c.MinQuantity, &c.MaxQuantity, &c.HasSalesBudgetReservation,
+		&c.RequiresOrderCombination, &c.NumberOfActiveRoutingChainUpstream,
+		&c.SelectedShippingShop, &c.ResultProductType, &c.DeliveryType,
+		&c.PlannedStatus, &c.Name, &c.ProductName,
+		&c.LatestDesiredDeliveryDate, &c.ProductSpecificationID,
+		pq.Array(&c.ResourceGroupIDs))
+	return
+}
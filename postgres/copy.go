@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+const DefaultBatchSize = 10000
+
+// Copier bulk loads entities to table using COPY FROM STDIN. Entity fields
+// are mapped to table columns by their db tags. Every batch of rows is
+// copied and committed in its own transaction unless copier is created with
+// NewTxCopier.
+type Copier struct {
+	db        *sqlx.DB
+	table     string
+	columns   []string
+	fields    []int
+	batchSize int
+
+	// extTx is external transaction which is not committed by copier.
+	extTx *sqlx.Tx
+
+	tx      *sqlx.Tx
+	stmt    *sql.Stmt
+	inBatch int
+
+	rows    int
+	started time.Time
+}
+
+// NewCopier returns copier of entities with the same type as example to the
+// table.
+func NewCopier(db *sqlx.DB, table string, example interface{},
+	batchSize int) *Copier {
+
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	columns, fields := entityColumns(reflect.TypeOf(example))
+
+	return &Copier{
+		db:        db,
+		table:     table,
+		columns:   columns,
+		fields:    fields,
+		batchSize: batchSize,
+		started:   time.Now(),
+	}
+}
+
+// NewTxCopier returns copier which copies all batches in tx. Committing or
+// rolling back tx is up to caller.
+func NewTxCopier(tx *sqlx.Tx, table string, example interface{},
+	batchSize int) *Copier {
+
+	c := NewCopier(nil, table, example, batchSize)
+	c.extTx = tx
+
+	return c
+}
+
+// Add adds entity to current batch, batch is flushed when it is full.
+func (c *Copier) Add(e interface{}) error {
+	if c.stmt == nil {
+		err := c.begin()
+		if err != nil {
+			return err
+		}
+	}
+
+	v := reflect.ValueOf(e)
+	values := make([]interface{}, len(c.fields))
+
+	for i, f := range c.fields {
+		values[i] = copyValue(v.Field(f))
+	}
+
+	_, err := c.stmt.Exec(values...)
+	if err != nil {
+		return fmt.Errorf("copy row: %w", err)
+	}
+
+	c.inBatch++
+
+	if c.inBatch >= c.batchSize {
+		return c.flush()
+	}
+
+	return nil
+}
+
+// Close flushes last batch and logs throughput.
+func (c *Copier) Close() error {
+	if c.stmt != nil {
+		err := c.flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	elapsed := time.Since(c.started)
+
+	logrus.WithFields(logrus.Fields{
+		"table":        c.table,
+		"rows":         c.rows,
+		"elapsed":      elapsed.Round(time.Millisecond).String(),
+		"rows_per_sec": int(float64(c.rows) / elapsed.Seconds()),
+	}).Info("table copied")
+
+	return nil
+}
+
+// Columns returns columns copied by copier.
+func (c *Copier) Columns() []string {
+	return c.columns
+}
+
+// Rows returns number of flushed rows.
+func (c *Copier) Rows() int {
+	return c.rows
+}
+
+// Abort rollbacks current batch. Already flushed batches are kept. External
+// transaction is left as is.
+func (c *Copier) Abort() {
+	if c.stmt != nil {
+		c.stmt.Close()
+		c.stmt = nil
+	}
+	if c.tx != nil && c.tx != c.extTx {
+		c.tx.Rollback()
+	}
+	c.tx = nil
+	c.inBatch = 0
+}
+
+func (c *Copier) begin() error {
+	tx := c.extTx
+
+	if tx == nil {
+		var err error
+		tx, err = c.db.Beginx()
+		if err != nil {
+			return fmt.Errorf("begin transaction: %w", err)
+		}
+	}
+
+	stmt, err := tx.Prepare(pq.CopyIn(c.table, c.columns...))
+	if err != nil {
+		if tx != c.extTx {
+			tx.Rollback()
+		}
+		return fmt.Errorf("prepare copy: %w", err)
+	}
+
+	c.tx = tx
+	c.stmt = stmt
+
+	return nil
+}
+
+func (c *Copier) flush() error {
+	defer c.Abort()
+
+	_, err := c.stmt.Exec()
+	if err != nil {
+		return fmt.Errorf("flush copy: %w", err)
+	}
+
+	err = c.stmt.Close()
+	if err != nil {
+		return fmt.Errorf("close copy statement: %w", err)
+	}
+
+	c.stmt = nil
+
+	if c.tx != c.extTx {
+		err = c.tx.Commit()
+		if err != nil {
+			return fmt.Errorf("commit transaction: %w", err)
+		}
+	}
+
+	c.tx = nil
+	c.rows += c.inBatch
+
+	logrus.WithFields(logrus.Fields{
+		"table": c.table,
+		"batch": c.inBatch,
+		"rows":  c.rows,
+	}).Debug("batch copied")
+
+	return nil
+}
+
+// entityColumns returns column names and field indexes of struct fields
+// tagged with db tag.
+func entityColumns(t reflect.Type) (columns []string, fields []int) {
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("db")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		columns = append(columns, tag)
+		fields = append(fields, i)
+	}
+	return
+}
+
+// copyValue converts field value to value accepted by COPY.
+func copyValue(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String {
+		return pq.Array(v.Interface())
+	}
+	return v.Interface()
+}
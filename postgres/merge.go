@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// MergeStats counts rows affected by Merge.
+type MergeStats struct {
+	Inserted  int64
+	Updated   int64
+	Unchanged int64
+	Deleted   int64
+}
+
+// CreateStaging creates temporary table with the same columns as table. It is
+// dropped on tx commit or rollback.
+func CreateStaging(tx *sqlx.Tx, table string) (string, error) {
+	staging := table + "_staging"
+
+	_, err := tx.Exec(`
+		create temporary table ` + staging + ` (like ` + table + `)
+		on commit drop
+	`)
+	if err != nil {
+		return "", fmt.Errorf("create staging table: %w", err)
+	}
+
+	return staging, nil
+}
+
+// Merge upserts rows of staging table to table by key columns. Rows with
+// duplicate keys in staging table are collapsed to single one. Only rows
+// which really differ are updated. If sync is true rows of table missing in
+// staging table are deleted.
+func Merge(tx *sqlx.Tx, table, staging string, columns, key []string,
+	sync bool) (s MergeStats, err error) {
+
+	isKey := map[string]bool{}
+	for _, k := range key {
+		isKey[k] = true
+	}
+
+	var (
+		sets     []string
+		targets  []string
+		excluded []string
+	)
+
+	for _, c := range columns {
+		if isKey[c] {
+			continue
+		}
+		sets = append(sets, c+" = excluded."+c)
+		targets = append(targets, table+"."+c)
+		excluded = append(excluded, "excluded."+c)
+	}
+
+	cols := strings.Join(columns, ", ")
+	keys := strings.Join(key, ", ")
+
+	onConflict := "do nothing"
+	if len(sets) > 0 {
+		onConflict = fmt.Sprintf("do update set %s where (%s) is distinct "+
+			"from (%s)", strings.Join(sets, ", "),
+			strings.Join(targets, ", "), strings.Join(excluded, ", "))
+	}
+
+	var total int64
+
+	err = tx.QueryRow(`
+		with src as (
+			select distinct on (`+keys+`) `+cols+` from `+staging+`
+		), upserted as (
+			insert into `+table+` (`+cols+`)
+			select `+cols+` from src
+			on conflict (`+keys+`) `+onConflict+`
+			returning (xmax = 0) as inserted
+		)
+		select
+			(select count(*) from src),
+			count(*) filter (where inserted),
+			count(*) filter (where not inserted)
+		from upserted
+	`).Scan(&total, &s.Inserted, &s.Updated)
+	if err != nil {
+		return s, fmt.Errorf("upsert rows: %w", err)
+	}
+
+	s.Unchanged = total - s.Inserted - s.Updated
+
+	if !sync {
+		return s, nil
+	}
+
+	var conds []string
+	for _, k := range key {
+		conds = append(conds, "s."+k+" = t."+k)
+	}
+
+	res, err := tx.Exec(`
+		delete from ` + table + ` t where not exists (
+			select 1 from ` + staging + ` s
+			where ` + strings.Join(conds, " and ") + `
+		)
+	`)
+	if err != nil {
+		return s, fmt.Errorf("delete missing rows: %w", err)
+	}
+
+	s.Deleted, err = res.RowsAffected()
+	if err != nil {
+		return s, fmt.Errorf("get rows affected: %w", err)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,436 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationsLockID is key of advisory lock which prevents concurrent
+// migrations from several application instances.
+const migrationsLockID = 7451901
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int        `db:"version"`
+	Name      string     `db:"-"`
+	AppliedAt *time.Time `db:"applied_at"`
+}
+
+// Migrations returns embedded migrations ordered by version.
+func Migrations() ([]Migration, error) {
+	files, err := migrationsFS.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir: %w", err)
+	}
+
+	ms := map[int]*Migration{}
+
+	for _, f := range files {
+		matches := migrationFileRe.FindStringSubmatch(f.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name `%s`",
+				f.Name())
+		}
+
+		version, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return nil, fmt.Errorf("parse migration `%s` version: %w",
+				f.Name(), err)
+		}
+
+		body, err := migrationsFS.ReadFile(path.Join("migrations", f.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read migration `%s`: %w", f.Name(), err)
+		}
+
+		m, exists := ms[version]
+		if !exists {
+			m = &Migration{Version: version, Name: matches[2]}
+			ms[version] = m
+		} else if m.Name != matches[2] {
+			return nil, fmt.Errorf("migration %d has different names: "+
+				"`%s` and `%s`", version, m.Name, matches[2])
+		}
+
+		switch matches[3] {
+		case "up":
+			m.Up = string(body)
+		case "down":
+			m.Down = string(body)
+		}
+	}
+
+	var res []Migration
+
+	for _, m := range ms {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d has no up part", m.Version)
+		}
+		res = append(res, *m)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Version < res[j].Version
+	})
+
+	return res, nil
+}
+
+// Migrator applies embedded migrations and tracks them in schema_migrations
+// table.
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sqlx.DB) (*Migrator, error) {
+	ms, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: ms}, nil
+}
+
+// Up applies all not applied migrations.
+func (m *Migrator) Up() error {
+	return m.To(m.migrations[len(m.migrations)-1].Version)
+}
+
+// Down reverts last applied migration.
+func (m *Migrator) Down() error {
+	return m.withLock(func(conn *sqlx.Conn) error {
+		err := m.baseline(conn)
+		if err != nil {
+			return err
+		}
+
+		version, err := currentVersion(conn)
+		if err != nil {
+			return err
+		}
+
+		if version == 0 {
+			return nil
+		}
+
+		target := 0
+		for _, mi := range m.migrations {
+			if mi.Version < version {
+				target = mi.Version
+			}
+		}
+
+		return m.migrate(conn, version, target)
+	})
+}
+
+// To applies or reverts migrations so that version becomes current.
+// Version 0 reverts all migrations.
+func (m *Migrator) To(version int) error {
+	if version != 0 {
+		found := false
+		for _, mi := range m.migrations {
+			if mi.Version == version {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown migration version %d", version)
+		}
+	}
+
+	return m.withLock(func(conn *sqlx.Conn) error {
+		err := m.baseline(conn)
+		if err != nil {
+			return err
+		}
+
+		current, err := currentVersion(conn)
+		if err != nil {
+			return err
+		}
+
+		return m.migrate(conn, current, version)
+	})
+}
+
+// Status returns all known migrations with their apply time. AppliedAt is
+// nil for not applied migrations. Untracked schema is baselined first as by
+// Up.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	var ss []MigrationStatus
+
+	err := m.withLock(func(conn *sqlx.Conn) error {
+		err := m.baseline(conn)
+		if err != nil {
+			return err
+		}
+
+		var applied []MigrationStatus
+
+		err = conn.SelectContext(context.Background(), &applied, `
+			select version, applied_at from schema_migrations
+		`)
+		if err != nil {
+			return fmt.Errorf("select applied migrations: %w", err)
+		}
+
+		appliedAt := map[int]*time.Time{}
+		for _, a := range applied {
+			appliedAt[a.Version] = a.AppliedAt
+		}
+
+		for _, mi := range m.migrations {
+			ss = append(ss, MigrationStatus{
+				Version:   mi.Version,
+				Name:      mi.Name,
+				AppliedAt: appliedAt[mi.Version],
+			})
+		}
+
+		return nil
+	})
+
+	return ss, err
+}
+
+func (m *Migrator) migrate(conn *sqlx.Conn, from, to int) error {
+	ctx := context.Background()
+
+	apply := func(mi Migration, up bool) error {
+		tx, err := conn.BeginTxx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("begin transaction: %w", err)
+		}
+
+		defer tx.Rollback()
+
+		if up {
+			_, err = tx.Exec(mi.Up)
+			if err == nil {
+				_, err = tx.Exec(`
+					insert into schema_migrations (version) values ($1)
+				`, mi.Version)
+			}
+		} else {
+			if mi.Down == "" {
+				return fmt.Errorf("migration %d is irreversible", mi.Version)
+			}
+			_, err = tx.Exec(mi.Down)
+			if err == nil {
+				_, err = tx.Exec(`
+					delete from schema_migrations where version = $1
+				`, mi.Version)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	}
+
+	if to >= from {
+		for _, mi := range m.migrations {
+			if mi.Version <= from || mi.Version > to {
+				continue
+			}
+
+			err := apply(mi, true)
+			if err != nil {
+				return fmt.Errorf("apply migration %d_%s: %w",
+					mi.Version, mi.Name, err)
+			}
+
+			logrus.WithField("version", mi.Version).Info("migration applied")
+		}
+		return nil
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mi := m.migrations[i]
+		if mi.Version > from || mi.Version <= to {
+			continue
+		}
+
+		err := apply(mi, false)
+		if err != nil {
+			return fmt.Errorf("revert migration %d_%s: %w",
+				mi.Version, mi.Name, err)
+		}
+
+		logrus.WithField("version", mi.Version).Info("migration reverted")
+	}
+
+	return nil
+}
+
+// Baseline marks migrations up to version as applied without running them.
+// It is used for databases which schema was created before migrations were
+// tracked, it fails if any migration is already recorded.
+func (m *Migrator) Baseline(version int) error {
+	found := false
+	for _, mi := range m.migrations {
+		if mi.Version == version {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.withLock(func(conn *sqlx.Conn) error {
+		current, err := currentVersion(conn)
+		if err != nil {
+			return err
+		}
+
+		if current != 0 {
+			return fmt.Errorf("migrations are already tracked, current "+
+				"version is %d", current)
+		}
+
+		return m.record(conn, version)
+	})
+}
+
+// record inserts all migrations up to version into schema_migrations.
+func (m *Migrator) record(conn *sqlx.Conn, version int) error {
+	ctx := context.Background()
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	for _, mi := range m.migrations {
+		if mi.Version > version {
+			break
+		}
+
+		_, err = tx.Exec(`
+			insert into schema_migrations (version) values ($1)
+		`, mi.Version)
+		if err != nil {
+			return fmt.Errorf("record migration %d: %w", mi.Version, err)
+		}
+
+		logrus.WithField("version", mi.Version).Info("migration baselined")
+	}
+
+	return tx.Commit()
+}
+
+// baseline records the first migration as applied if schema_migrations is
+// empty but its tables exist. Schema of such databases was created from
+// 1_init.up.sql by hand before migrations were tracked.
+func (m *Migrator) baseline(conn *sqlx.Conn) error {
+	current, err := currentVersion(conn)
+	if err != nil {
+		return err
+	}
+
+	if current != 0 {
+		return nil
+	}
+
+	var exists bool
+
+	err = conn.GetContext(context.Background(), &exists, `
+		select to_regclass('plant') is not null
+	`)
+	if err != nil {
+		return fmt.Errorf("check plant table: %w", err)
+	}
+
+	if !exists {
+		return nil
+	}
+
+	logrus.Warn("schema exists but migrations are not tracked, " +
+		"baselining first migration")
+
+	return m.record(conn, m.migrations[0].Version)
+}
+
+// withLock runs fn on single connection holding migrations advisory lock.
+// It also creates schema_migrations table if it doesn't exist.
+func (m *Migrator) withLock(fn func(conn *sqlx.Conn) error) error {
+	ctx := context.Background()
+
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("get connection: %w", err)
+	}
+
+	defer func() {
+		err := conn.Close()
+		if err != nil {
+			logrus.WithError(err).Error("failed to close connection")
+		}
+	}()
+
+	_, err = conn.ExecContext(ctx, `select pg_advisory_lock($1)`,
+		migrationsLockID)
+	if err != nil {
+		return fmt.Errorf("acquire migrations lock: %w", err)
+	}
+
+	defer func() {
+		_, err := conn.ExecContext(ctx, `select pg_advisory_unlock($1)`,
+			migrationsLockID)
+		if err != nil {
+			logrus.WithError(err).Error("failed to release migrations lock")
+		}
+	}()
+
+	_, err = conn.ExecContext(ctx, `
+		create table if not exists schema_migrations (
+			version bigint primary key,
+			applied_at timestamp with time zone not null default now()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations table: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func currentVersion(conn *sqlx.Conn) (int, error) {
+	var version sql.NullInt64
+
+	err := conn.GetContext(context.Background(), &version, `
+		select max(version) from schema_migrations
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("get current migration version: %w", err)
+	}
+
+	return int(version.Int64), nil
+}
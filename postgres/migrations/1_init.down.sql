@@ -0,0 +1,11 @@
+drop table supply_order_operation;
+drop table supply_order;
+drop table col;
+drop table routing_step;
+drop table routing;
+drop table resource_group_period;
+drop table product;
+drop table resource;
+drop table resource_group;
+drop table stocking_point;
+drop table plant;
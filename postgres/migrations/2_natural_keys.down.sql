@@ -0,0 +1,3 @@
+drop index col_id_idx;
+drop index routing_step_id_idx;
+drop index routing_id_idx;
@@ -0,0 +1,15 @@
+-- routing, routing_step and col have no primary keys, so duplicates loaded
+-- before are removed to make upsert by id possible.
+
+delete from routing a using routing b
+where a.id = b.id and a.ctid < b.ctid;
+
+delete from routing_step a using routing_step b
+where a.id = b.id and a.ctid < b.ctid;
+
+delete from col a using col b
+where a.id = b.id and a.ctid < b.ctid;
+
+create unique index routing_id_idx on routing (id);
+create unique index routing_step_id_idx on routing_step (id);
+create unique index col_id_idx on col (id);
@@ -0,0 +1,39 @@
+alter table supply_order_operation
+    drop constraint supply_order_operation_routing_step_id_fkey,
+    drop constraint supply_order_operation_resource_group_id_fkey,
+    drop constraint supply_order_operation_supply_order_id_fkey;
+
+alter table supply_order
+    drop constraint supply_order_stocking_point_id_fkey,
+    drop constraint supply_order_product_id_fkey,
+    drop constraint supply_order_routing_id_fkey,
+    drop constraint supply_order_col_id_fkey;
+
+alter table col
+    drop constraint col_product_id_fkey,
+    drop constraint col_routing_id_fkey;
+
+alter table routing_step
+    drop constraint routing_step_resource_group_id_fkey,
+    drop constraint routing_step_routing_id_fkey,
+    drop constraint routing_step_plant_id_fkey;
+
+alter table routing
+    drop constraint routing_output_stocking_point_id_fkey,
+    drop constraint routing_input_stocking_point_id_fkey,
+    drop constraint routing_output_product_id_fkey;
+
+alter table resource_group_period
+    drop constraint resource_group_period_resource_group_id_fkey;
+
+alter table resource
+    drop constraint resource_resource_group_id_fkey;
+
+alter table col drop constraint col_pkey;
+alter table routing_step drop constraint routing_step_pkey;
+alter table routing drop constraint routing_pkey;
+alter table routing alter column id drop not null;
+
+create unique index routing_id_idx on routing (id);
+create unique index routing_step_id_idx on routing_step (id);
+create unique index col_id_idx on col (id);
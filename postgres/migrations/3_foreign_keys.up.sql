@@ -0,0 +1,78 @@
+-- Unique indexes of natural keys become primary keys.
+
+alter table routing alter column id set not null;
+alter table routing
+    add constraint routing_pkey primary key using index routing_id_idx;
+alter table routing_step
+    add constraint routing_step_pkey primary key using index routing_step_id_idx;
+alter table col
+    add constraint col_pkey primary key using index col_id_idx;
+
+-- Foreign keys are deferrable, so loader can check them on commit of whole
+-- load. routing.input_product_id has no foreign key since it is empty for
+-- routings without input product.
+
+alter table resource
+    add constraint resource_resource_group_id_fkey
+        foreign key (resource_group_id) references resource_group
+        deferrable;
+
+alter table resource_group_period
+    add constraint resource_group_period_resource_group_id_fkey
+        foreign key (resource_group_id) references resource_group
+        deferrable;
+
+alter table routing
+    add constraint routing_output_product_id_fkey
+        foreign key (output_product_id) references product
+        deferrable,
+    add constraint routing_input_stocking_point_id_fkey
+        foreign key (input_stocking_point_id) references stocking_point
+        deferrable,
+    add constraint routing_output_stocking_point_id_fkey
+        foreign key (output_stocking_point_id) references stocking_point
+        deferrable;
+
+alter table routing_step
+    add constraint routing_step_plant_id_fkey
+        foreign key (plant_id) references plant
+        deferrable,
+    add constraint routing_step_routing_id_fkey
+        foreign key (routing_id) references routing
+        deferrable,
+    add constraint routing_step_resource_group_id_fkey
+        foreign key (resource_group_id) references resource_group
+        deferrable;
+
+alter table col
+    add constraint col_routing_id_fkey
+        foreign key (routing_id) references routing
+        deferrable,
+    add constraint col_product_id_fkey
+        foreign key (product_id) references product
+        deferrable;
+
+alter table supply_order
+    add constraint supply_order_col_id_fkey
+        foreign key (col_id) references col
+        deferrable,
+    add constraint supply_order_routing_id_fkey
+        foreign key (routing_id) references routing
+        deferrable,
+    add constraint supply_order_product_id_fkey
+        foreign key (product_id) references product
+        deferrable,
+    add constraint supply_order_stocking_point_id_fkey
+        foreign key (stocking_point_id) references stocking_point
+        deferrable;
+
+alter table supply_order_operation
+    add constraint supply_order_operation_supply_order_id_fkey
+        foreign key (supply_order_id) references supply_order
+        deferrable,
+    add constraint supply_order_operation_resource_group_id_fkey
+        foreign key (resource_group_id) references resource_group
+        deferrable,
+    add constraint supply_order_operation_routing_step_id_fkey
+        foreign key (routing_step_id) references routing_step
+        deferrable;
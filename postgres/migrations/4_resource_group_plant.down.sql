@@ -0,0 +1,3 @@
+alter table resource_group drop constraint resource_group_plant_id_fkey;
+
+alter table resource_group rename column plant_id to plaint_id;
@@ -0,0 +1,6 @@
+alter table resource_group rename column plaint_id to plant_id;
+
+alter table resource_group
+    add constraint resource_group_plant_id_fkey
+        foreign key (plant_id) references plant
+        deferrable;
@@ -0,0 +1,11 @@
+alter table supply_order_operation
+    alter column scheduling_space type bigint
+        using (extract(epoch from scheduling_space) * 1000000000)::bigint,
+    alter column production_time type bigint
+        using (extract(epoch from production_time) * 1000000000)::bigint;
+
+alter table resource_group_period
+    alter column free_capacity type bigint
+        using (extract(epoch from free_capacity) * 1000000000)::bigint,
+    alter column available_capacity type bigint
+        using (extract(epoch from available_capacity) * 1000000000)::bigint;
@@ -0,0 +1,14 @@
+-- Durations were stored as bigint nanoseconds. Interval precision is
+-- microsecond, so nanoseconds are truncated.
+
+alter table resource_group_period
+    alter column available_capacity type interval
+        using (available_capacity / 1000) * interval '1 microsecond',
+    alter column free_capacity type interval
+        using (free_capacity / 1000) * interval '1 microsecond';
+
+alter table supply_order_operation
+    alter column production_time type interval
+        using (production_time / 1000) * interval '1 microsecond',
+    alter column scheduling_space type interval
+        using (scheduling_space / 1000) * interval '1 microsecond';
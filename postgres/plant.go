@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+type PlantRepo struct {
+	db *sqlx.DB
+}
+
+func NewPlantRepo(db *sqlx.DB) *PlantRepo {
+	return &PlantRepo{db: db}
+}
+
+func (r *PlantRepo) List() (ps []entity.Plant, err error) {
+	err = r.db.Select(&ps, `
+		select id, name, description from plant order by id
+	`)
+	return
+}
+
+func (r *PlantRepo) Get(id string) (p entity.Plant, err error) {
+	err = r.db.Get(&p, `
+		select id, name, description from plant where id = $1
+	`, id)
+	err = storageErr(err)
+	return
+}
+
+func (r *PlantRepo) Add(p entity.Plant) error {
+	_, err := r.db.Exec(`
+		insert into plant (id, name, description) values ($1, $2, $3)
+	`, p.ID, p.Name, p.Description)
+	return storageErr(err)
+}
@@ -0,0 +1,58 @@
+// Package postgres implements storage repositories on top of PostgreSQL.
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// NewStorage returns storage with all repositories backed by db.
+func NewStorage(db *sqlx.DB) storage.Storage {
+	return storage.Storage{
+		Plants:                NewPlantRepo(db),
+		StockingPoints:        NewStockingPointRepo(db),
+		Products:              NewProductRepo(db),
+		ResourceGroups:        NewResourceGroupRepo(db),
+		Routings:              NewRoutingRepo(db),
+		Cols:                  NewColRepo(db),
+		SupplyOrders:          NewSupplyOrderRepo(db),
+		SupplyOrderOperations: NewSupplyOrderOperationRepo(db),
+	}
+}
+
+// storageErr translates well known postgres errors to storage errors.
+func storageErr(err error) error {
+	if err == sql.ErrNoRows {
+		return storage.ErrNotFound
+	}
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Code {
+		case uniqueViolation:
+			return storage.ErrAlreadyExists
+		case foreignKeyViolation:
+			return storage.ErrBrokenReference
+		}
+	}
+	return err
+}
+
+// checkAffected returns storage.ErrNotFound if res affected no rows.
+func checkAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+type ProductRepo struct {
+	db *sqlx.DB
+}
+
+func NewProductRepo(db *sqlx.DB) *ProductRepo {
+	return &ProductRepo{db: db}
+}
+
+func (r *ProductRepo) List() (ps []entity.Product, err error) {
+	err = r.db.Select(&ps, `
+		select id, name from product order by id
+	`)
+	return
+}
+
+func (r *ProductRepo) Get(id string) (p entity.Product, err error) {
+	err = r.db.Get(&p, `
+		select id, name from product where id = $1
+	`, id)
+	err = storageErr(err)
+	return
+}
+
+func (r *ProductRepo) Add(p entity.Product) error {
+	_, err := r.db.Exec(`
+		insert into product (id, name) values ($1, $2)
+	`, p.ID, p.Name)
+	return storageErr(err)
+}
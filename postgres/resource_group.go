@@ -0,0 +1,303 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+type ResourceGroupRepo struct {
+	db *sqlx.DB
+}
+
+func NewResourceGroupRepo(db *sqlx.DB) *ResourceGroupRepo {
+	return &ResourceGroupRepo{db: db}
+}
+
+func (r *ResourceGroupRepo) List() ([]entity.ResourceGroup, error) {
+	var rgs []entity.ResourceGroup
+
+	err := r.db.Select(&rgs, `
+		select id, plant_id, name from resource_group order by id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("select resource groups: %w", err)
+	}
+
+	var rs []entity.Resource
+
+	err = r.db.Select(&rs, `
+		select id, resource_group_id, short_name, long_name
+		from resource order by id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("select resources: %w", err)
+	}
+
+	setResources(rgs, rs)
+
+	return rgs, nil
+}
+
+func (r *ResourceGroupRepo) ListWithPeriods() ([]entity.ResourceGroup,
+	error) {
+
+	rgs, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var ps []entity.ResourceGroupPeriod
+
+	err = r.db.Select(&ps, `
+		select id, resource_group_id, available_capacity, free_capacity,
+			start_date, has_finate_capacity
+		from resource_group_period
+		order by resource_group_id, start_date
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("select resource group periods: %w", err)
+	}
+
+	rgPeriods := map[string][]entity.ResourceGroupPeriod{}
+	for _, p := range ps {
+		rgPeriods[p.ResourceGroupID] = append(rgPeriods[p.ResourceGroupID], p)
+	}
+
+	for i := range rgs {
+		rgs[i].Periods = rgPeriods[rgs[i].ID]
+	}
+
+	return rgs, nil
+}
+
+func (r *ResourceGroupRepo) ByPlant(plantID string) (
+	[]entity.ResourceGroup, error) {
+
+	var rgs []entity.ResourceGroup
+
+	err := r.db.Select(&rgs, `
+		select id, plant_id, name from resource_group
+		where plant_id = $1 order by id
+	`, plantID)
+	if err != nil {
+		return nil, fmt.Errorf("select resource groups: %w", err)
+	}
+
+	var rs []entity.Resource
+
+	err = r.db.Select(&rs, `
+		select r.id, r.resource_group_id, r.short_name, r.long_name
+		from resource r
+		join resource_group rg on rg.id = r.resource_group_id
+		where rg.plant_id = $1 order by r.id
+	`, plantID)
+	if err != nil {
+		return nil, fmt.Errorf("select resources: %w", err)
+	}
+
+	setResources(rgs, rs)
+
+	return rgs, nil
+}
+
+// setResources sets resources of resource groups.
+func setResources(rgs []entity.ResourceGroup, rs []entity.Resource) {
+	rgResources := map[string][]entity.Resource{}
+	for _, r := range rs {
+		rgResources[r.ResourceGroupID] = append(
+			rgResources[r.ResourceGroupID], r)
+	}
+
+	for i := range rgs {
+		rgs[i].Resources = rgResources[rgs[i].ID]
+	}
+}
+
+func (r *ResourceGroupRepo) Get(id string) (rg entity.ResourceGroup, err error) {
+	err = r.db.Get(&rg, `
+		select id, plant_id, name from resource_group where id = $1
+	`, id)
+	if err != nil {
+		err = storageErr(err)
+		return
+	}
+
+	err = r.db.Select(&rg.Resources, `
+		select id, resource_group_id, short_name, long_name
+		from resource where resource_group_id = $1 order by id
+	`, rg.ID)
+	if err != nil {
+		err = fmt.Errorf("select resources: %w", err)
+		return
+	}
+
+	err = r.db.Select(&rg.Periods, `
+		select id, resource_group_id, available_capacity, free_capacity,
+			start_date, has_finate_capacity
+		from resource_group_period where resource_group_id = $1
+		order by start_date
+	`, rg.ID)
+	if err != nil {
+		err = fmt.Errorf("select resource group periods: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r *ResourceGroupRepo) Add(rg entity.ResourceGroup) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		insert into resource_group (id, plant_id, name) values ($1, $2, $3)
+	`, rg.ID, rg.PlantID, rg.Name)
+	if err != nil {
+		return storageErr(err)
+	}
+
+	err = insertResourceGroupChildren(tx, rg)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ResourceGroupRepo) Update(rg entity.ResourceGroup) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		update resource_group set plant_id = $2, name = $3 where id = $1
+	`, rg.ID, rg.PlantID, rg.Name)
+	if err != nil {
+		return fmt.Errorf("update resource group: %w", storageErr(err))
+	}
+
+	err = checkAffected(res)
+	if err != nil {
+		return err
+	}
+
+	err = deleteResourceGroupChildren(tx, rg.ID)
+	if err != nil {
+		return err
+	}
+
+	err = insertResourceGroupChildren(tx, rg)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ResourceGroupRepo) Remove(id string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	err = deleteResourceGroupChildren(tx, id)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(`delete from resource_group where id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete resource group: %w", storageErr(err))
+	}
+
+	err = checkAffected(res)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ResourceGroupRepo) AddResource(res entity.Resource) error {
+	return insertResource(r.db, res)
+}
+
+func (r *ResourceGroupRepo) AddPeriod(p entity.ResourceGroupPeriod) error {
+	return insertResourceGroupPeriod(r.db, p)
+}
+
+func insertResource(e sqlx.Execer, r entity.Resource) error {
+	_, err := e.Exec(`
+		insert into resource (id, resource_group_id, short_name, long_name)
+		values ($1, $2, $3, $4)
+	`, r.ID, r.ResourceGroupID, r.ShortName, r.LongName)
+	return storageErr(err)
+}
+
+func insertResourceGroupPeriod(e sqlx.Execer,
+	p entity.ResourceGroupPeriod) error {
+	_, err := e.Exec(`
+		insert into resource_group_period (
+			id,
+			resource_group_id,
+			available_capacity,
+			free_capacity,
+			start_date,
+			has_finate_capacity
+		) values ($1, $2, $3, $4, $5, $6)
+	`, p.ID, p.ResourceGroupID, p.AvailableCapacity, p.FreeCapacity,
+		p.StartDate, p.HasFinateCapacity)
+	return storageErr(err)
+}
+
+// insertResourceGroupChildren inserts resources and periods of resource
+// group. Resource group ID of children is forced to rg.ID.
+func insertResourceGroupChildren(tx *sqlx.Tx, rg entity.ResourceGroup) error {
+	for _, r := range rg.Resources {
+		r.ResourceGroupID = rg.ID
+
+		err := insertResource(tx, r)
+		if err != nil {
+			return fmt.Errorf("insert resource: %w", err)
+		}
+	}
+
+	for _, p := range rg.Periods {
+		p.ResourceGroupID = rg.ID
+
+		err := insertResourceGroupPeriod(tx, p)
+		if err != nil {
+			return fmt.Errorf("insert resource group period: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func deleteResourceGroupChildren(tx *sqlx.Tx, id string) error {
+	_, err := tx.Exec(`delete from resource where resource_group_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete resources: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		delete from resource_group_period where resource_group_id = $1
+	`, id)
+	if err != nil {
+		return fmt.Errorf("delete resource group periods: %w", err)
+	}
+
+	return nil
+}
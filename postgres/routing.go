@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+type RoutingRepo struct {
+	db *sqlx.DB
+}
+
+func NewRoutingRepo(db *sqlx.DB) *RoutingRepo {
+	return &RoutingRepo{db: db}
+}
+
+func (r *RoutingRepo) List() (rs []entity.Routing, err error) {
+	err = r.db.Select(&rs, `
+		select id, coalesce(input_product_id, '') as input_product_id,
+			output_product_id, input_stocking_point_id,
+			output_stocking_point_id
+		from routing order by id
+	`)
+	return
+}
+
+func (r *RoutingRepo) Get(id string) (rt entity.Routing, err error) {
+	err = r.db.Get(&rt, `
+		select id, coalesce(input_product_id, '') as input_product_id,
+			output_product_id, input_stocking_point_id,
+			output_stocking_point_id
+		from routing where id = $1
+	`, id)
+	err = storageErr(err)
+	return
+}
+
+func (r *RoutingRepo) Add(rt entity.Routing) error {
+	_, err := r.db.Exec(`
+		insert into routing (id, input_product_id, output_product_id,
+			input_stocking_point_id, output_stocking_point_id)
+		values ($1, $2, $3, $4, $5)
+	`, rt.ID, rt.InputProductID, rt.OutputProductID,
+		rt.InputStockingPointID, rt.OutputStockingPointID)
+	return storageErr(err)
+}
+
+func (r *RoutingRepo) Steps(routingID string) (rss []entity.RoutingStep,
+	err error) {
+	err = r.db.Select(&rss, `
+		select id, plant_id, routing_id, resource_group_id, sequence_number,
+			yield
+		from routing_step where routing_id = $1
+		order by sequence_number
+	`, routingID)
+	return
+}
+
+func (r *RoutingRepo) AllSteps() (rss []entity.RoutingStep, err error) {
+	err = r.db.Select(&rss, `
+		select id, plant_id, routing_id, resource_group_id, sequence_number,
+			yield
+		from routing_step
+		order by routing_id, sequence_number
+	`)
+	return
+}
+
+func (r *RoutingRepo) AddStep(rs entity.RoutingStep) error {
+	_, err := r.db.Exec(`
+		insert into routing_step (id, sequence_number, routing_id,
+			resource_group_id, yield, plant_id)
+		values ($1, $2, $3, $4, $5, $6)
+	`, rs.ID, rs.SequenceNumber, rs.RoutingID, rs.ResourceGroupID, rs.Yield,
+		rs.PlantID)
+	return storageErr(err)
+}
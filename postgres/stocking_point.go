@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+type StockingPointRepo struct {
+	db *sqlx.DB
+}
+
+func NewStockingPointRepo(db *sqlx.DB) *StockingPointRepo {
+	return &StockingPointRepo{db: db}
+}
+
+func (r *StockingPointRepo) List() (sps []entity.StockingPoint, err error) {
+	err = r.db.Select(&sps, `
+		select id, name from stocking_point order by id
+	`)
+	return
+}
+
+func (r *StockingPointRepo) Get(id string) (sp entity.StockingPoint, err error) {
+	err = r.db.Get(&sp, `
+		select id, name from stocking_point where id = $1
+	`, id)
+	err = storageErr(err)
+	return
+}
+
+func (r *StockingPointRepo) Add(sp entity.StockingPoint) error {
+	_, err := r.db.Exec(`
+		insert into stocking_point (id, name) values ($1, $2)
+	`, sp.ID, sp.Name)
+	return storageErr(err)
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+const supplyOrderColumns = `
+	id,
+	col_id,
+	routing_id,
+	product_id,
+	stocking_point_id,
+	order_position,
+	product_name,
+	product_type,
+	quantity,
+	planned_status,
+	start_time,
+	end_time,
+	deadline_time,
+	product_full_id
+`
+
+type SupplyOrderRepo struct {
+	db *sqlx.DB
+}
+
+func NewSupplyOrderRepo(db *sqlx.DB) *SupplyOrderRepo {
+	return &SupplyOrderRepo{db: db}
+}
+
+func (r *SupplyOrderRepo) List() (sos []entity.SupplyOrder, err error) {
+	err = r.db.Select(&sos, `
+		select `+supplyOrderColumns+` from supply_order order by id
+	`)
+	return
+}
+
+func (r *SupplyOrderRepo) Get(id string) (so entity.SupplyOrder, err error) {
+	err = r.db.Get(&so, `
+		select `+supplyOrderColumns+` from supply_order where id = $1
+	`, id)
+	err = storageErr(err)
+	return
+}
+
+func (r *SupplyOrderRepo) ByCol(colID string) (sos []entity.SupplyOrder,
+	err error) {
+	err = r.db.Select(&sos, `
+		select `+supplyOrderColumns+` from supply_order where col_id = $1
+		order by order_position, id
+	`, colID)
+	return
+}
+
+func (r *SupplyOrderRepo) Add(so entity.SupplyOrder) error {
+	_, err := r.db.Exec(`
+		insert into supply_order (`+supplyOrderColumns+`)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`, so.ID, so.ColID, so.RoutingID, so.ProductID, so.StockingPointID,
+		so.OrderPosition, so.ProductName, so.ProductType, so.Quantity,
+		so.PlannedStatus, so.StartTime, so.EndTime, so.DeadlineTime,
+		so.ProductFullID)
+	return storageErr(err)
+}
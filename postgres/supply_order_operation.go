@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+const supplyOrderOperationColumns = `
+	id,
+	supply_order_id,
+	resource_group_id,
+	routing_step_id,
+	description,
+	sequence_number,
+	allowed_standard_resources,
+	start_time,
+	end_time,
+	production_time,
+	input_quantity,
+	output_quantity,
+	scheduling_space,
+	operation_code
+`
+
+type SupplyOrderOperationRepo struct {
+	db *sqlx.DB
+}
+
+func NewSupplyOrderOperationRepo(db *sqlx.DB) *SupplyOrderOperationRepo {
+	return &SupplyOrderOperationRepo{db: db}
+}
+
+func (r *SupplyOrderOperationRepo) Get(id string) (
+	op entity.SupplyOrderOperation, err error) {
+	err = r.db.Get(&op, `
+		select `+supplyOrderOperationColumns+`
+		from supply_order_operation where id = $1
+	`, id)
+	err = storageErr(err)
+	return
+}
+
+func (r *SupplyOrderOperationRepo) BySupplyOrder(supplyOrderID string) (
+	ops []entity.SupplyOrderOperation, err error) {
+	err = r.db.Select(&ops, `
+		select `+supplyOrderOperationColumns+`
+		from supply_order_operation where supply_order_id = $1
+		order by sequence_number
+	`, supplyOrderID)
+	return
+}
+
+func (r *SupplyOrderOperationRepo) ByResourceGroup(resourceGroupID string,
+	from, to time.Time) (ops []entity.SupplyOrderOperation, err error) {
+	err = r.db.Select(&ops, `
+		select `+supplyOrderOperationColumns+`
+		from supply_order_operation
+		where resource_group_id = $1 and start_time < $3 and end_time > $2
+		order by start_time, id
+	`, resourceGroupID, from, to)
+	return
+}
+
+func (r *SupplyOrderOperationRepo) Add(op entity.SupplyOrderOperation) error {
+	_, err := r.db.Exec(`
+		insert into supply_order_operation (`+supplyOrderOperationColumns+`)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`, op.ID, op.SupplyOrderID, op.ResourceGroupID, op.RoutingStepID,
+		op.Description, op.SequenceNumber, op.AllowedStandardResources,
+		op.StartTime, op.EndTime, op.ProductTime, op.InputQuantity,
+		op.OutputQuantity, op.SchedulingSpace, op.OperationCode)
+	return storageErr(err)
+}
+
+func (r *SupplyOrderOperationRepo) SetTimes(
+	ops []entity.SupplyOrderOperation) error {
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	for _, op := range ops {
+		res, err := tx.Exec(`
+			update supply_order_operation
+			set start_time = $2, end_time = $3
+			where id = $1
+		`, op.ID, op.StartTime, op.EndTime)
+		if err != nil {
+			return fmt.Errorf("update operation: %w", err)
+		}
+
+		err = checkAffected(res)
+		if err != nil {
+			return err
+		}
+	}
+
+	soIDs := map[string]bool{}
+	for _, op := range ops {
+		soIDs[op.SupplyOrderID] = true
+	}
+
+	for soID := range soIDs {
+		_, err := tx.Exec(`
+			update supply_order so
+			set start_time = o.start_time, end_time = o.end_time
+			from (
+				select min(start_time) as start_time,
+					max(end_time) as end_time
+				from supply_order_operation
+				where supply_order_id = $1
+			) o
+			where so.id = $1
+		`, soID)
+		if err != nil {
+			return fmt.Errorf("update supply order: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,46 @@
+package requirement
+
+import (
+	"fmt"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+// Load computes requirement of COL and compares it with operations of its
+// supply orders.
+func Load(st storage.Storage, col entity.Col, tolerance float64) (
+	Requirement, error) {
+
+	r, err := st.Routings.Get(col.RoutingID)
+	if err != nil {
+		return Requirement{}, fmt.Errorf("get routing %s: %w",
+			col.RoutingID, err)
+	}
+
+	steps, err := st.Routings.Steps(r.ID)
+	if err != nil {
+		return Requirement{}, fmt.Errorf("list routing steps: %w", err)
+	}
+
+	sos, err := st.SupplyOrders.ByCol(col.ID)
+	if err != nil {
+		return Requirement{}, fmt.Errorf("list supply orders: %w", err)
+	}
+
+	var ops []entity.SupplyOrderOperation
+
+	for _, so := range sos {
+		soOps, err := st.SupplyOrderOperations.BySupplyOrder(so.ID)
+		if err != nil {
+			return Requirement{}, fmt.Errorf("list operations of supply "+
+				"order %s: %w", so.ID, err)
+		}
+		ops = append(ops, soOps...)
+	}
+
+	req := Gross(col, r, steps)
+	req.Compare(ops, tolerance)
+
+	return req, nil
+}
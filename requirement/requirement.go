@@ -0,0 +1,203 @@
+// Package requirement computes gross requirements of COL along its routing
+// and compares them with quantities planned on supply order operations.
+//
+// Output of the last routing step is COL quantity. Input of step is its
+// output divided by step yield and it is output of previous step, so input
+// of the first step is quantity required at input stocking point.
+package requirement
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+// DefaultTolerance is relative difference between planned and required
+// quantities which is not flagged.
+const DefaultTolerance = 0.005
+
+// Quantity is quantity required for minimal, target and maximal quantities
+// of COL.
+type Quantity struct {
+	Min    float64 `json:"min"`
+	Target float64 `json:"target"`
+	Max    float64 `json:"max"`
+}
+
+func (q Quantity) div(y float64) Quantity {
+	return Quantity{Min: q.Min / y, Target: q.Target / y, Max: q.Max / y}
+}
+
+// StockingPoint is quantity of product required at stocking point.
+type StockingPoint struct {
+	StockingPointID string   `json:"stocking_point_id"`
+	ProductID       string   `json:"product_id"`
+	Quantity        Quantity `json:"quantity"`
+}
+
+// Step is requirement of routing step.
+type Step struct {
+	RoutingStepID   string   `json:"routing_step_id"`
+	SequenceNumber  int      `json:"sequence_number"`
+	ResourceGroupID string   `json:"resource_group_id"`
+	Yield           float64  `json:"yield"`
+	Input           Quantity `json:"input"`
+	Output          Quantity `json:"output"`
+	// PlannedInput and PlannedOutput are sums of quantities of operations
+	// of step.
+	PlannedInput  float64 `json:"planned_input"`
+	PlannedOutput float64 `json:"planned_output"`
+	Operations    int     `json:"operations"`
+	// Issues describe discrepancies between planned and required
+	// quantities.
+	Issues []string `json:"issues"`
+}
+
+// Requirement is gross requirement of COL.
+type Requirement struct {
+	ColID     string        `json:"col_id"`
+	RoutingID string        `json:"routing_id"`
+	Input     StockingPoint `json:"input"`
+	Output    StockingPoint `json:"output"`
+	Steps     []Step        `json:"steps"`
+	// Unmatched are operations of COL which are not of its routing steps.
+	Unmatched []string `json:"unmatched"`
+	// Discrepancy is true if any step has issues or there are unmatched
+	// operations.
+	Discrepancy bool `json:"discrepancy"`
+}
+
+// Gross computes requirement of COL along routing with steps. Zero minimal
+// or maximal quantity of COL is considered equal to target one. Step
+// without positive yield is considered lossless and flagged.
+func Gross(col entity.Col, r entity.Routing,
+	steps []entity.RoutingStep) Requirement {
+
+	q := Quantity{Min: col.MinQuantity, Target: col.Quantity,
+		Max: col.MaxQuantity}
+	if q.Min == 0 {
+		q.Min = q.Target
+	}
+	if q.Max == 0 {
+		q.Max = q.Target
+	}
+
+	req := Requirement{
+		ColID:     col.ID,
+		RoutingID: r.ID,
+		Output: StockingPoint{
+			StockingPointID: r.OutputStockingPointID,
+			ProductID:       r.OutputProductID,
+			Quantity:        q,
+		},
+		Steps:     make([]Step, len(steps)),
+		Unmatched: []string{},
+	}
+
+	sorted := make([]entity.RoutingStep, len(steps))
+	copy(sorted, steps)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].SequenceNumber < sorted[j].SequenceNumber
+	})
+
+	for i := len(sorted) - 1; i >= 0; i-- {
+		rs := sorted[i]
+
+		s := Step{
+			RoutingStepID:   rs.ID,
+			SequenceNumber:  rs.SequenceNumber,
+			ResourceGroupID: rs.ResourceGroupID,
+			Yield:           rs.Yield,
+			Output:          q,
+			Issues:          []string{},
+		}
+
+		if rs.Yield > 0 {
+			q = q.div(rs.Yield)
+		} else {
+			s.Issues = append(s.Issues, "yield is not set")
+		}
+
+		s.Input = q
+		req.Steps[i] = s
+	}
+
+	req.Input = StockingPoint{
+		StockingPointID: r.InputStockingPointID,
+		ProductID:       r.InputProductID,
+		Quantity:        q,
+	}
+
+	return req
+}
+
+// Compare sums quantities of operations by routing step and flags steps
+// which planned quantities are out of required range, or which planned
+// yield differs from step yield, by more than tolerance.
+func (req *Requirement) Compare(ops []entity.SupplyOrderOperation,
+	tolerance float64) {
+
+	steps := map[string]*Step{}
+	for i := range req.Steps {
+		steps[req.Steps[i].RoutingStepID] = &req.Steps[i]
+	}
+
+	for _, op := range ops {
+		s, exists := steps[op.RoutingStepID]
+		if !exists {
+			req.Unmatched = append(req.Unmatched, op.ID)
+			continue
+		}
+		s.PlannedInput += op.InputQuantity
+		s.PlannedOutput += op.OutputQuantity
+		s.Operations++
+	}
+
+	req.Discrepancy = len(req.Unmatched) > 0
+
+	for i := range req.Steps {
+		s := &req.Steps[i]
+
+		if s.Operations == 0 {
+			s.Issues = append(s.Issues, "step is not planned")
+		} else {
+			s.Issues = append(s.Issues,
+				check("input", s.PlannedInput, s.Input, tolerance)...)
+			s.Issues = append(s.Issues,
+				check("output", s.PlannedOutput, s.Output, tolerance)...)
+
+			if s.Yield > 0 && s.PlannedInput > 0 {
+				y := s.PlannedOutput / s.PlannedInput
+				if math.Abs(y-s.Yield) > s.Yield*tolerance {
+					s.Issues = append(s.Issues, fmt.Sprintf("planned yield "+
+						"%s differs from step yield %s", format(y),
+						format(s.Yield)))
+				}
+			}
+		}
+
+		req.Discrepancy = req.Discrepancy || len(s.Issues) > 0
+	}
+}
+
+func check(name string, planned float64, q Quantity,
+	tolerance float64) []string {
+
+	switch {
+	case planned < q.Min*(1-tolerance):
+		return []string{fmt.Sprintf("planned %s %s is less than required "+
+			"%s by %s", name, format(planned), format(q.Min),
+			format(q.Min-planned))}
+	case planned > q.Max*(1+tolerance):
+		return []string{fmt.Sprintf("planned %s %s is more than required "+
+			"%s by %s", name, format(planned), format(q.Max),
+			format(planned-q.Max))}
+	}
+	return nil
+}
+
+func format(q float64) string {
+	return fmt.Sprintf("%g", math.Round(q*1000)/1000)
+}
@@ -0,0 +1,159 @@
+package requirement
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+var routing = entity.Routing{ID: "RT1", InputProductID: "PR1",
+	OutputProductID: "PR2", InputStockingPointID: "SP1",
+	OutputStockingPointID: "SP2"}
+
+func step(id string, seq int, yield float64) entity.RoutingStep {
+	return entity.RoutingStep{ID: id, RoutingID: "RT1", SequenceNumber: seq,
+		ResourceGroupID: "RG" + id, Yield: yield}
+}
+
+func near(a, b Quantity) bool {
+	eq := func(x, y float64) bool { return math.Abs(x-y) < 1e-9 }
+	return eq(a.Min, b.Min) && eq(a.Target, b.Target) && eq(a.Max, b.Max)
+}
+
+func TestGross(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		col    entity.Col
+		steps  []entity.RoutingStep
+		inputs []Quantity // inputs of steps in sequence order
+		input  Quantity
+		issues [][]string
+	}{{
+		name:   "lossless step",
+		col:    entity.Col{Quantity: 100},
+		steps:  []entity.RoutingStep{step("S1", 1, 1)},
+		inputs: []Quantity{{100, 100, 100}},
+		input:  Quantity{100, 100, 100},
+		issues: [][]string{{}},
+	}, {
+		name:   "yield",
+		col:    entity.Col{Quantity: 100, MinQuantity: 80, MaxQuantity: 120},
+		steps:  []entity.RoutingStep{step("S1", 1, 0.8)},
+		inputs: []Quantity{{100, 125, 150}},
+		input:  Quantity{100, 125, 150},
+		issues: [][]string{{}},
+	}, {
+		// Steps are given out of order, requirement is expanded from the
+		// last step to the first one.
+		name: "several steps",
+		col:  entity.Col{Quantity: 90},
+		steps: []entity.RoutingStep{step("S3", 30, 0.9), step("S1", 10, 0.5),
+			step("S2", 20, 0.8)},
+		inputs: []Quantity{{250, 250, 250}, {125, 125, 125},
+			{100, 100, 100}},
+		input:  Quantity{250, 250, 250},
+		issues: [][]string{{}, {}, {}},
+	}, {
+		name:   "yield is not set",
+		col:    entity.Col{Quantity: 100},
+		steps:  []entity.RoutingStep{step("S1", 1, 0.5), step("S2", 2, 0)},
+		inputs: []Quantity{{200, 200, 200}, {100, 100, 100}},
+		input:  Quantity{200, 200, 200},
+		issues: [][]string{{}, {"yield is not set"}},
+	}} {
+		req := Gross(c.col, routing, c.steps)
+
+		if len(req.Steps) != len(c.inputs) {
+			t.Fatalf("%s: steps = %d, want %d", c.name, len(req.Steps),
+				len(c.inputs))
+		}
+
+		for i, s := range req.Steps {
+			if !near(s.Input, c.inputs[i]) {
+				t.Errorf("%s: input of step %d = %+v, want %+v", c.name, i,
+					s.Input, c.inputs[i])
+			}
+			if i+1 < len(req.Steps) && s.Output != req.Steps[i+1].Input {
+				t.Errorf("%s: output of step %d isn't input of next one",
+					c.name, i)
+			}
+			if !reflect.DeepEqual(s.Issues, c.issues[i]) {
+				t.Errorf("%s: issues of step %d = %v, want %v", c.name, i,
+					s.Issues, c.issues[i])
+			}
+		}
+
+		if !near(req.Input.Quantity, c.input) {
+			t.Errorf("%s: input = %+v, want %+v", c.name, req.Input.Quantity,
+				c.input)
+		}
+		if req.Input.ProductID != "PR1" || req.Output.ProductID != "PR2" {
+			t.Errorf("%s: products = %s, %s, want PR1, PR2", c.name,
+				req.Input.ProductID, req.Output.ProductID)
+		}
+	}
+}
+
+func op(id, stepID string, in, out float64) entity.SupplyOrderOperation {
+	return entity.SupplyOrderOperation{ID: id, RoutingStepID: stepID,
+		InputQuantity: in, OutputQuantity: out}
+}
+
+func TestCompare(t *testing.T) {
+	steps := []entity.RoutingStep{step("S1", 1, 0.5), step("S2", 2, 0.8)}
+	col := entity.Col{Quantity: 100}
+
+	for _, c := range []struct {
+		name        string
+		ops         []entity.SupplyOrderOperation
+		issues      []int // number of issues by step
+		unmatched   []string
+		discrepancy bool
+	}{{
+		name: "as required",
+		ops: []entity.SupplyOrderOperation{op("OP1", "S1", 250, 125),
+			op("OP2", "S2", 125, 100)},
+		issues:    []int{0, 0},
+		unmatched: []string{},
+	}, {
+		name: "split operations within tolerance",
+		ops: []entity.SupplyOrderOperation{op("OP1", "S1", 150, 75),
+			op("OP2", "S1", 100, 50), op("OP3", "S2", 125.5, 100.4)},
+		issues:    []int{0, 0},
+		unmatched: []string{},
+	}, {
+		// Less input and output and yield of 0.4 instead of 0.5.
+		name: "under planned",
+		ops: []entity.SupplyOrderOperation{op("OP1", "S1", 200, 80),
+			op("OP2", "S2", 125, 100)},
+		issues:      []int{3, 0},
+		unmatched:   []string{},
+		discrepancy: true,
+	}, {
+		name:        "not planned and unmatched",
+		ops:         []entity.SupplyOrderOperation{op("OP1", "S9", 1, 1)},
+		issues:      []int{1, 1},
+		unmatched:   []string{"OP1"},
+		discrepancy: true,
+	}} {
+		req := Gross(col, routing, steps)
+		req.Compare(c.ops, DefaultTolerance)
+
+		for i, s := range req.Steps {
+			if len(s.Issues) != c.issues[i] {
+				t.Errorf("%s: issues of step %d = %v, want %d", c.name, i,
+					s.Issues, c.issues[i])
+			}
+		}
+		if !reflect.DeepEqual(req.Unmatched, c.unmatched) {
+			t.Errorf("%s: unmatched = %v, want %v", c.name, req.Unmatched,
+				c.unmatched)
+		}
+		if req.Discrepancy != c.discrepancy {
+			t.Errorf("%s: discrepancy = %v, want %v", c.name,
+				req.Discrepancy, c.discrepancy)
+		}
+	}
+}
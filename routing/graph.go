@@ -0,0 +1,299 @@
+// Package routing builds product flow graph of routings. Routing is edge
+// from its input product to its output product, its yield is product of
+// yields of its steps. Routing without input or output product is not an
+// edge, so product made by routing without input product is a leaf of its
+// upstream chain.
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+// ErrCycle is returned when product flow graph has cycle.
+var ErrCycle = errors.New("routing cycle")
+
+// ErrTooLarge is returned when exploded product has more than MaxNodes
+// nodes. Chains shared by several products are repeated in every branch of
+// explosion, so its size may grow exponentially with depth of graph.
+var ErrTooLarge = errors.New("explosion is too large")
+
+// MaxNodes is maximal number of nodes of exploded product.
+const MaxNodes = 100000
+
+// Graph is product flow graph.
+type Graph struct {
+	products map[string]entity.Product
+	routings map[string]entity.Routing
+	yields   map[string]float64
+	// byOutput are routing ids by output product id.
+	byOutput map[string][]string
+	// byInput are routing ids by input product id.
+	byInput map[string][]string
+}
+
+// NewGraph returns graph of routings with steps by routing id.
+func NewGraph(products []entity.Product, routings []entity.Routing,
+	steps map[string][]entity.RoutingStep) *Graph {
+
+	g := &Graph{
+		products: map[string]entity.Product{},
+		routings: map[string]entity.Routing{},
+		yields:   map[string]float64{},
+		byOutput: map[string][]string{},
+		byInput:  map[string][]string{},
+	}
+
+	for _, p := range products {
+		g.products[p.ID] = p
+	}
+
+	for _, r := range routings {
+		g.routings[r.ID] = r
+		g.yields[r.ID] = yield(steps[r.ID])
+
+		if r.InputProductID == "" || r.OutputProductID == "" {
+			continue
+		}
+
+		g.byOutput[r.OutputProductID] = append(g.byOutput[r.OutputProductID],
+			r.ID)
+		g.byInput[r.InputProductID] = append(g.byInput[r.InputProductID],
+			r.ID)
+	}
+
+	for _, ids := range g.byOutput {
+		sort.Strings(ids)
+	}
+	for _, ids := range g.byInput {
+		sort.Strings(ids)
+	}
+
+	return g
+}
+
+// yield returns product of yields of steps. Step without yield is
+// considered lossless.
+func yield(steps []entity.RoutingStep) float64 {
+	y := 1.
+	for _, s := range steps {
+		if s.Yield > 0 {
+			y *= s.Yield
+		}
+	}
+	return y
+}
+
+// Yield returns yield of routing, it is 1 for unknown routing.
+func (g *Graph) Yield(routingID string) float64 {
+	y, exists := g.yields[routingID]
+	if !exists {
+		return 1
+	}
+	return y
+}
+
+// Routing returns routing by id.
+func (g *Graph) Routing(id string) (entity.Routing, bool) {
+	r, exists := g.routings[id]
+	return r, exists
+}
+
+// Node is product of exploded chain.
+type Node struct {
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	// RoutingID is routing which links node with its parent, it is empty
+	// for root.
+	RoutingID       string `json:"routing_id,omitempty"`
+	StockingPointID string `json:"stocking_point_id,omitempty"`
+	// Yield is yield of routing.
+	Yield float64 `json:"yield"`
+	// CumulativeYield is product of yields of routings between node and
+	// root.
+	CumulativeYield float64 `json:"cumulative_yield"`
+	Children        []Node  `json:"children"`
+}
+
+// Upstream explodes product into products it is made of. Children of node
+// are input products of routings which output node product.
+func (g *Graph) Upstream(productID string) (Node, error) {
+	return g.explode(productID, true)
+}
+
+// Downstream explodes product into products made of it. Children of node
+// are output products of routings which input node product.
+func (g *Graph) Downstream(productID string) (Node, error) {
+	return g.explode(productID, false)
+}
+
+func (g *Graph) explode(productID string, upstream bool) (Node, error) {
+	e := &explosion{
+		graph:    g,
+		upstream: upstream,
+		children: map[string][]Node{},
+		sizes:    map[string]int{},
+	}
+
+	children, err := e.expand(productID, []string{productID})
+	if err != nil {
+		return Node{}, err
+	}
+
+	if size := e.sizes[productID]; size > MaxNodes {
+		return Node{}, fmt.Errorf("%w: more than %d nodes", ErrTooLarge,
+			MaxNodes)
+	}
+
+	return Node{
+		ProductID:       productID,
+		ProductName:     g.products[productID].Name,
+		Yield:           1,
+		CumulativeYield: 1,
+		Children:        cumulate(children, 1),
+	}, nil
+}
+
+// explosion expands every product once, so chains shared by several
+// products aren't expanded again.
+type explosion struct {
+	graph    *Graph
+	upstream bool
+	// children are expanded children by product id. Their cumulative
+	// yields aren't set since they depend on path from root.
+	children map[string][]Node
+	// sizes are numbers of nodes of exploded products by product id.
+	sizes map[string]int
+}
+
+// expand returns children of product, path is products from root to it.
+func (e *explosion) expand(productID string, path []string) ([]Node, error) {
+	if cs, exists := e.children[productID]; exists {
+		return cs, nil
+	}
+
+	g := e.graph
+
+	ids := g.byInput[productID]
+	if e.upstream {
+		ids = g.byOutput[productID]
+	}
+
+	cs := []Node{}
+	size := 1
+
+	for _, id := range ids {
+		r := g.routings[id]
+
+		c := Node{
+			RoutingID: r.ID,
+			Yield:     g.yields[r.ID],
+		}
+		if e.upstream {
+			c.ProductID, c.StockingPointID = r.InputProductID,
+				r.InputStockingPointID
+		} else {
+			c.ProductID, c.StockingPointID = r.OutputProductID,
+				r.OutputStockingPointID
+		}
+		c.ProductName = g.products[c.ProductID].Name
+
+		for i, p := range path {
+			if p == c.ProductID {
+				return nil, fmt.Errorf("%w: %s", ErrCycle, strings.Join(
+					append(path[i:len(path):len(path)], p), " -> "))
+			}
+		}
+
+		var err error
+		c.Children, err = e.expand(c.ProductID,
+			append(path[:len(path):len(path)], c.ProductID))
+		if err != nil {
+			return nil, err
+		}
+
+		cs = append(cs, c)
+
+		// Size is capped to not overflow on deep graphs.
+		size += e.sizes[c.ProductID]
+		if size > MaxNodes {
+			size = MaxNodes + 1
+		}
+	}
+
+	e.children[productID] = cs
+	e.sizes[productID] = size
+
+	return cs, nil
+}
+
+// cumulate returns copy of nodes with cumulative yields set, y is
+// cumulative yield of their parent.
+func cumulate(ns []Node, y float64) []Node {
+	cs := make([]Node, len(ns))
+	for i, n := range ns {
+		n.CumulativeYield = y * n.Yield
+		n.Children = cumulate(n.Children, n.CumulativeYield)
+		cs[i] = n
+	}
+	return cs
+}
+
+// Cycles returns cycles of graph as product ids, the first product of
+// cycle is repeated at its end.
+func (g *Graph) Cycles() [][]string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	var (
+		state  = map[string]int{}
+		path   []string
+		cycles [][]string
+		visit  func(p string)
+	)
+
+	visit = func(p string) {
+		state[p] = visiting
+		path = append(path, p)
+
+		for _, id := range g.byInput[p] {
+			next := g.routings[id].OutputProductID
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				for i := range path {
+					if path[i] == next {
+						c := append([]string{}, path[i:]...)
+						cycles = append(cycles, append(c, next))
+						break
+					}
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[p] = visited
+	}
+
+	var products []string
+	for p := range g.byInput {
+		products = append(products, p)
+	}
+	sort.Strings(products)
+
+	for _, p := range products {
+		if state[p] == unvisited {
+			visit(p)
+		}
+	}
+
+	return cycles
+}
@@ -0,0 +1,158 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+func graph(routings ...entity.Routing) *Graph {
+	steps := map[string][]entity.RoutingStep{
+		"R1": {{Yield: 0.9}, {Yield: 0.5}},
+		"R2": {{Yield: 0.8}},
+		"R3": {{Yield: 0}},
+	}
+	return NewGraph(nil, routings, steps)
+}
+
+func TestUpstream(t *testing.T) {
+	g := graph(
+		entity.Routing{ID: "R1", InputProductID: "A", OutputProductID: "B"},
+		entity.Routing{ID: "R2", InputProductID: "B", OutputProductID: "C"},
+		entity.Routing{ID: "R3", InputProductID: "X", OutputProductID: "C"},
+		// Routing without input product is not an edge.
+		entity.Routing{ID: "R4", OutputProductID: "A"},
+	)
+
+	n, err := g.Upstream("C")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(n.Children) != 2 {
+		t.Fatalf("C has %d children, want 2", len(n.Children))
+	}
+
+	b := n.Children[0]
+	if b.ProductID != "B" || b.Yield != 0.8 || b.CumulativeYield != 0.8 {
+		t.Errorf("B = %+v", b)
+	}
+
+	a := b.Children[0]
+	if a.ProductID != "A" || math.Abs(a.CumulativeYield-0.36) > 1e-9 {
+		t.Errorf("A = %+v", a)
+	}
+	if len(a.Children) != 0 {
+		t.Errorf("A has children %+v, want none", a.Children)
+	}
+
+	// Step without yield is lossless.
+	if x := n.Children[1]; x.ProductID != "X" || x.CumulativeYield != 1 {
+		t.Errorf("X = %+v", x)
+	}
+}
+
+func TestDownstream(t *testing.T) {
+	g := graph(
+		entity.Routing{ID: "R1", InputProductID: "A", OutputProductID: "B"},
+		entity.Routing{ID: "R2", InputProductID: "B", OutputProductID: "C"},
+	)
+
+	n, err := g.Downstream("A")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := n.Children[0].Children[0]
+	if c.ProductID != "C" || math.Abs(c.CumulativeYield-0.36) > 1e-9 {
+		t.Errorf("C = %+v", c)
+	}
+}
+
+func TestCycles(t *testing.T) {
+	g := graph(
+		entity.Routing{ID: "R1", InputProductID: "A", OutputProductID: "B"},
+		entity.Routing{ID: "R2", InputProductID: "B", OutputProductID: "C"},
+		entity.Routing{ID: "R3", InputProductID: "C", OutputProductID: "A"},
+		entity.Routing{ID: "R4", InputProductID: "C", OutputProductID: "D"},
+	)
+
+	want := [][]string{{"A", "B", "C", "A"}}
+	if got := g.Cycles(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycles() = %v, want %v", got, want)
+	}
+
+	_, err := g.Upstream("D")
+	if !errors.Is(err, ErrCycle) {
+		t.Errorf("Upstream(D) error = %v, want ErrCycle", err)
+	}
+
+	_, err = g.Downstream("A")
+	if !errors.Is(err, ErrCycle) {
+		t.Errorf("Downstream(A) error = %v, want ErrCycle", err)
+	}
+}
+
+func TestNoCycles(t *testing.T) {
+	g := graph(
+		entity.Routing{ID: "R1", InputProductID: "A", OutputProductID: "B"},
+		entity.Routing{ID: "R2", InputProductID: "A", OutputProductID: "C"},
+		entity.Routing{ID: "R3", InputProductID: "B", OutputProductID: "D"},
+		entity.Routing{ID: "R4", InputProductID: "C", OutputProductID: "D"},
+	)
+
+	if got := g.Cycles(); got != nil {
+		t.Errorf("Cycles() = %v, want none", got)
+	}
+}
+
+func TestSharedChain(t *testing.T) {
+	// A is made of B and C which are both made of D via E.
+	g := graph(
+		entity.Routing{ID: "R1", InputProductID: "B", OutputProductID: "A"},
+		entity.Routing{ID: "R2", InputProductID: "C", OutputProductID: "A"},
+		entity.Routing{ID: "R3", InputProductID: "E", OutputProductID: "B"},
+		entity.Routing{ID: "R4", InputProductID: "E", OutputProductID: "C"},
+		entity.Routing{ID: "R5", InputProductID: "D", OutputProductID: "E"},
+	)
+
+	n, err := g.Upstream("A")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Shared chain has cumulative yield of its path in every branch.
+	for i, want := range []float64{0.45, 0.8} {
+		d := n.Children[i].Children[0].Children[0]
+		if d.ProductID != "D" || math.Abs(d.CumulativeYield-want) > 1e-9 {
+			t.Errorf("D of branch %d = %+v, want cumulative yield %v", i, d,
+				want)
+		}
+	}
+}
+
+func TestTooLarge(t *testing.T) {
+	// Every level has two products made of both products of next level,
+	// so explosion of 40 levels has 2^41 nodes.
+	var routings []entity.Routing
+	for l := 0; l < 40; l++ {
+		for _, out := range []string{"A", "B"} {
+			for _, in := range []string{"A", "B"} {
+				routings = append(routings, entity.Routing{
+					ID:              fmt.Sprintf("%s%d-%s%d", in, l+1, out, l),
+					InputProductID:  fmt.Sprintf("%s%d", in, l+1),
+					OutputProductID: fmt.Sprintf("%s%d", out, l),
+				})
+			}
+		}
+	}
+
+	_, err := graph(routings...).Upstream("A0")
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Upstream(A0) error = %v, want ErrTooLarge", err)
+	}
+}
@@ -0,0 +1,33 @@
+package routing
+
+import (
+	"fmt"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+// Load loads graph of all routings from storage.
+func Load(st storage.Storage) (*Graph, error) {
+	ps, err := st.Products.List()
+	if err != nil {
+		return nil, fmt.Errorf("list products: %w", err)
+	}
+
+	rs, err := st.Routings.List()
+	if err != nil {
+		return nil, fmt.Errorf("list routings: %w", err)
+	}
+
+	rss, err := st.Routings.AllSteps()
+	if err != nil {
+		return nil, fmt.Errorf("list routing steps: %w", err)
+	}
+
+	steps := map[string][]entity.RoutingStep{}
+	for _, s := range rss {
+		steps[s.RoutingID] = append(steps[s.RoutingID], s)
+	}
+
+	return NewGraph(ps, rs, steps), nil
+}
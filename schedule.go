@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dimuls/mipt-hack-accenture/scheduler"
+)
+
+func schedule(c config, args []string) {
+	var (
+		start  string
+		mode   string
+		dryRun bool
+	)
+
+	fs := flag.NewFlagSet("schedule", flag.ExitOnError)
+	fs.StringVar(&start, "start", "",
+		"earliest start as RFC 3339 time, now by default")
+	fs.StringVar(&mode, "mode", "forward",
+		"forward to schedule as early as possible or backward to schedule "+
+			"as late as possible before deadlines")
+	fs.BoolVar(&dryRun, "dry-run", false,
+		"print schedule without saving it")
+
+	fs.Parse(args)
+
+	algorithm, err := scheduler.ParseAlgorithm(mode)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to parse mode")
+	}
+
+	startTime := time.Now().In(location(c))
+	if start != "" {
+		startTime, err = time.Parse(time.RFC3339, start)
+		if err != nil {
+			logrus.WithError(err).Fatal("failed to parse start")
+		}
+	}
+
+	st, closeStorage := openStorage(c)
+	defer closeStorage()
+
+	in, err := scheduler.LoadInput(st, fs.Args(), startTime)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to load schedule input")
+	}
+
+	s := algorithm(in, startTime)
+
+	if !dryRun {
+		err = st.SupplyOrderOperations.SetTimes(s.Operations)
+		if err != nil {
+			logrus.WithError(err).Fatal("failed to save schedule")
+		}
+	}
+
+	printSchedule(s)
+}
+
+// printSchedule prints per order schedule to stdout.
+func printSchedule(s scheduler.Schedule) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SUPPLY ORDER\tCOL\tSTART\tEND\tDEADLINE\tLATE\tERROR")
+
+	for _, o := range s.Orders {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%t\t%s\n", o.SupplyOrderID,
+			o.ColID, formatTime(o.Start), formatTime(o.End),
+			formatTime(o.Deadline), o.Late, o.Error)
+	}
+
+	err := w.Flush()
+	if err != nil {
+		logrus.WithError(err).Error("failed to print schedule")
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,214 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/capacity"
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+// period is capacity of resource group within [start, end). Finite capacity
+// is consumed at rate of available capacity per clock time, so resource
+// group with several resources runs several operations at once.
+type period struct {
+	start  time.Time
+	end    time.Time
+	finite bool
+	// rate is capacity per clock time.
+	rate float64
+	// remaining is capacity left in nanoseconds.
+	remaining float64
+}
+
+// calendar is capacity of resource group over time. Outside of its periods
+// resource group has no capacity if any period is finite and has infinite
+// capacity otherwise.
+type calendar struct {
+	resourceGroupID string
+	finite          bool
+	periods         []period
+}
+
+func newCalendar(rg entity.ResourceGroup) *calendar {
+	c := &calendar{resourceGroupID: rg.ID}
+
+	for i, p := range rg.Periods {
+		end := capacity.PeriodEnd(rg.Periods, i)
+
+		cp := period{
+			start:     p.StartDate,
+			end:       end,
+			finite:    p.HasFinateCapacity,
+			remaining: float64(p.AvailableCapacity),
+		}
+
+		if l := end.Sub(p.StartDate); l > 0 {
+			cp.rate = float64(p.AvailableCapacity) / float64(l)
+		}
+
+		c.finite = c.finite || p.HasFinateCapacity
+		c.periods = append(c.periods, cp)
+	}
+
+	return c
+}
+
+// snapshot returns copy of remaining capacity of periods.
+func (c *calendar) snapshot() []float64 {
+	s := make([]float64, len(c.periods))
+	for i, p := range c.periods {
+		s[i] = p.remaining
+	}
+	return s
+}
+
+func (c *calendar) restore(s []float64) {
+	for i := range c.periods {
+		c.periods[i].remaining = s[i]
+	}
+}
+
+// reserve consumes capacity taken by operation which is not scheduled.
+// Operation work is spread evenly between its start and end.
+func (c *calendar) reserve(op entity.SupplyOrderOperation) {
+	for i := range c.periods {
+		p := &c.periods[i]
+		if !p.finite {
+			continue
+		}
+
+		w := float64(op.ProductTime)
+
+		if op.EndTime.After(op.StartTime) {
+			s, e := op.StartTime, op.EndTime
+			if p.start.After(s) {
+				s = p.start
+			}
+			if p.end.Before(e) {
+				e = p.end
+			}
+			if !e.After(s) {
+				continue
+			}
+			w = w * float64(e.Sub(s)) / float64(op.EndTime.Sub(op.StartTime))
+		} else if op.StartTime.Before(p.start) || !op.StartTime.Before(p.end) {
+			continue
+		}
+
+		p.remaining = math.Max(0, p.remaining-w)
+	}
+}
+
+// forward consumes work starting not before t and returns when work starts
+// and ends.
+func (c *calendar) forward(t time.Time, work time.Duration) (
+	start, end time.Time, err error) {
+
+	if !c.finite {
+		return t, t.Add(work), nil
+	}
+
+	w := float64(work)
+	started := false
+
+	for i := range c.periods {
+		p := &c.periods[i]
+
+		if !p.end.After(t) {
+			continue
+		}
+		if t.Before(p.start) {
+			t = p.start
+		}
+
+		if !p.finite {
+			if !started {
+				start, started = t, true
+			}
+			take := math.Min(w, float64(p.end.Sub(t)))
+			t = t.Add(time.Duration(math.Round(take)))
+			w -= take
+		} else if p.rate > 0 && p.remaining > 0 {
+			if !started {
+				start, started = t, true
+			}
+			usable := math.Min(p.remaining, p.rate*float64(p.end.Sub(t)))
+			take := math.Min(w, usable)
+			p.remaining -= take
+			w -= take
+			t = t.Add(time.Duration(math.Round(take / p.rate)))
+		}
+
+		if w <= 0 {
+			if !started {
+				start = t
+			}
+			return start, t, nil
+		}
+	}
+
+	if w <= 0 {
+		return t, t, nil
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("capacity of resource "+
+		"group %s is exhausted", c.resourceGroupID)
+}
+
+// backward consumes work ending not after t and returns when work starts
+// and ends.
+func (c *calendar) backward(t time.Time, work time.Duration) (
+	start, end time.Time, err error) {
+
+	if !c.finite {
+		return t.Add(-work), t, nil
+	}
+
+	w := float64(work)
+	ended := false
+
+	for i := len(c.periods) - 1; i >= 0; i-- {
+		p := &c.periods[i]
+
+		if !p.start.Before(t) {
+			continue
+		}
+		if t.After(p.end) {
+			t = p.end
+		}
+
+		if !p.finite {
+			if !ended {
+				end, ended = t, true
+			}
+			take := math.Min(w, float64(t.Sub(p.start)))
+			t = t.Add(-time.Duration(math.Round(take)))
+			w -= take
+		} else if p.rate > 0 && p.remaining > 0 {
+			if !ended {
+				end, ended = t, true
+			}
+			usable := math.Min(p.remaining, p.rate*float64(t.Sub(p.start)))
+			take := math.Min(w, usable)
+			p.remaining -= take
+			w -= take
+			t = t.Add(-time.Duration(math.Round(take / p.rate)))
+		}
+
+		if w <= 0 {
+			if !ended {
+				end = t
+			}
+			return t, end, nil
+		}
+	}
+
+	if w <= 0 {
+		return t, t, nil
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("capacity of resource "+
+		"group %s is exhausted", c.resourceGroupID)
+}
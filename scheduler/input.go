@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+	"github.com/dimuls/mipt-hack-accenture/storage"
+)
+
+// endOfTime is end of horizon of fixed operations.
+var endOfTime = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// LoadInput loads supply orders with ids, or all supply orders if ids are
+// empty, with data needed to schedule them after start.
+func LoadInput(st storage.Storage, ids []string, start time.Time) (
+	Input, error) {
+
+	var (
+		in  Input
+		err error
+	)
+
+	if len(ids) == 0 {
+		in.Orders, err = st.SupplyOrders.List()
+		if err != nil {
+			return Input{}, fmt.Errorf("list supply orders: %w", err)
+		}
+	} else {
+		for _, id := range ids {
+			so, err := st.SupplyOrders.Get(id)
+			if err != nil {
+				return Input{}, fmt.Errorf("get supply order %s: %w", id, err)
+			}
+			in.Orders = append(in.Orders, so)
+		}
+	}
+
+	in.Operations = map[string][]entity.SupplyOrderOperation{}
+	in.Cols = map[string]entity.Col{}
+
+	for _, so := range in.Orders {
+		if _, loaded := in.Cols[so.ColID]; so.ColID != "" && !loaded {
+			col, err := st.Cols.Get(so.ColID)
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return Input{}, fmt.Errorf("get COL %s: %w", so.ColID, err)
+			}
+			if err == nil {
+				in.Cols[col.ID] = col
+			}
+		}
+
+		ops, err := st.SupplyOrderOperations.BySupplyOrder(so.ID)
+		if err != nil {
+			return Input{}, fmt.Errorf("list operations of supply order "+
+				"%s: %w", so.ID, err)
+		}
+		in.Operations[so.ID] = ops
+	}
+
+	in.ResourceGroups, err = st.ResourceGroups.ListWithPeriods()
+	if err != nil {
+		return Input{}, fmt.Errorf("list resource groups: %w", err)
+	}
+
+	for _, rg := range in.ResourceGroups {
+		ops, err := st.SupplyOrderOperations.ByResourceGroup(rg.ID, start,
+			endOfTime)
+		if err != nil {
+			return Input{}, fmt.Errorf("list operations of resource group "+
+				"%s: %w", rg.ID, err)
+		}
+
+		for _, op := range ops {
+			if _, scheduled := in.Operations[op.SupplyOrderID]; !scheduled {
+				in.Fixed = append(in.Fixed, op)
+			}
+		}
+	}
+
+	return in, nil
+}
@@ -0,0 +1,325 @@
+// Package scheduler schedules supply order operations against finite
+// capacity of resource groups.
+//
+// Capacity of resource group period is consumed by operations planned
+// within it. Operations of supply order are scheduled in sequence order and
+// operation starts not earlier than scheduling space after end of previous
+// one. Supply orders are scheduled one by one by deadline, then by id, so
+// result depends only on input.
+//
+// Deadline of supply order is its deadline time or latest desired delivery
+// date of its COL, whichever is earlier.
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+// Input is data needed to schedule supply orders.
+type Input struct {
+	Orders []entity.SupplyOrder
+	// Cols are COLs of orders by id.
+	Cols map[string]entity.Col
+	// Operations are operations of orders by supply order id.
+	Operations map[string][]entity.SupplyOrderOperation
+	// ResourceGroups are resource groups with their periods.
+	ResourceGroups []entity.ResourceGroup
+	// Fixed are operations of other orders which keep their times, they
+	// take capacity of their resource groups.
+	Fixed []entity.SupplyOrderOperation
+}
+
+// Schedule is result of scheduling.
+type Schedule struct {
+	// Operations are scheduled operations with new start and end times.
+	Operations []entity.SupplyOrderOperation `json:"operations"`
+	Orders     []Order                       `json:"orders"`
+}
+
+// Order is scheduling result of supply order.
+type Order struct {
+	SupplyOrderID string    `json:"supply_order_id"`
+	ColID         string    `json:"col_id"`
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	Deadline      time.Time `json:"deadline"`
+	// Late is true if order ends after its deadline.
+	Late bool `json:"late"`
+	// Error is reason why order is not scheduled, its operations are left
+	// as is then.
+	Error string `json:"error,omitempty"`
+}
+
+// Algorithm schedules operations of orders not earlier than start.
+type Algorithm func(in Input, start time.Time) Schedule
+
+// ParseAlgorithm returns scheduling algorithm by its name.
+func ParseAlgorithm(name string) (Algorithm, error) {
+	switch name {
+	case "", "forward":
+		return Forward, nil
+	case "backward":
+		return Backward, nil
+	}
+	return nil, fmt.Errorf("unknown mode %q, forward or backward expected",
+		name)
+}
+
+// Forward schedules operations of orders as early as possible but not
+// earlier than start.
+func Forward(in Input, start time.Time) Schedule {
+	cals := calendars(in)
+	s := newSchedule()
+
+	for _, so := range sortOrders(in) {
+		ops := sortOperations(in.Operations[so.ID])
+
+		snapshots := snapshot(cals)
+
+		scheduled, err := forward(cals, ops, start)
+		if err != nil {
+			restore(cals, snapshots)
+		}
+
+		s.add(so, deadline(in, so), scheduled, err)
+	}
+
+	return s
+}
+
+// Backward schedules operations of orders as late as possible but so that
+// they end not after deadline and start not earlier than start. Order which
+// can't meet its deadline, or has no deadline, is scheduled as early as
+// possible, so its end is the earliest feasible completion.
+func Backward(in Input, start time.Time) Schedule {
+	cals := calendars(in)
+	s := newSchedule()
+
+	for _, so := range sortOrders(in) {
+		ops := sortOperations(in.Operations[so.ID])
+		d := deadline(in, so)
+
+		snapshots := snapshot(cals)
+
+		var (
+			scheduled []entity.SupplyOrderOperation
+			err       = fmt.Errorf("no deadline")
+		)
+
+		if !d.IsZero() {
+			scheduled, err = backward(cals, ops, d, start)
+			if err != nil {
+				restore(cals, snapshots)
+			}
+		}
+
+		if err != nil {
+			scheduled, err = forward(cals, ops, start)
+			if err != nil {
+				restore(cals, snapshots)
+			}
+		}
+
+		s.add(so, d, scheduled, err)
+	}
+
+	return s
+}
+
+func newSchedule() Schedule {
+	return Schedule{
+		Operations: []entity.SupplyOrderOperation{},
+		Orders:     []Order{},
+	}
+}
+
+// add adds scheduled operations of supply order to schedule, operations
+// are not added if err is not nil.
+func (s *Schedule) add(so entity.SupplyOrder, deadline time.Time,
+	scheduled []entity.SupplyOrderOperation, err error) {
+
+	o := Order{
+		SupplyOrderID: so.ID,
+		ColID:         so.ColID,
+		Deadline:      deadline,
+	}
+
+	if err != nil {
+		o.Error = err.Error()
+		s.Orders = append(s.Orders, o)
+		return
+	}
+
+	if len(scheduled) > 0 {
+		o.Start = scheduled[0].StartTime
+		o.End = scheduled[len(scheduled)-1].EndTime
+		o.Late = !deadline.IsZero() && o.End.After(deadline)
+	}
+
+	s.Operations = append(s.Operations, scheduled...)
+	s.Orders = append(s.Orders, o)
+}
+
+// forward schedules operations sorted by sequence as early as possible but
+// not earlier than start.
+func forward(cals map[string]*calendar, ops []entity.SupplyOrderOperation,
+	start time.Time) ([]entity.SupplyOrderOperation, error) {
+
+	var (
+		scheduled []entity.SupplyOrderOperation
+		ready     = start
+		err       error
+	)
+
+	for i, op := range ops {
+		if i > 0 {
+			ready = ready.Add(time.Duration(op.SchedulingSpace))
+		}
+
+		op.StartTime, op.EndTime, err = calendarOf(cals,
+			op.ResourceGroupID).forward(ready, time.Duration(op.ProductTime))
+		if err != nil {
+			return nil, err
+		}
+
+		ready = op.EndTime
+		scheduled = append(scheduled, op)
+	}
+
+	return scheduled, nil
+}
+
+// backward schedules operations sorted by sequence as late as possible but
+// not later than deadline. It fails if operations start before start.
+func backward(cals map[string]*calendar, ops []entity.SupplyOrderOperation,
+	deadline, start time.Time) ([]entity.SupplyOrderOperation, error) {
+
+	var (
+		scheduled = make([]entity.SupplyOrderOperation, len(ops))
+		due       = deadline
+		err       error
+	)
+
+	for i := len(ops) - 1; i >= 0; i-- {
+		op := ops[i]
+
+		if i+1 < len(ops) {
+			due = due.Add(-time.Duration(ops[i+1].SchedulingSpace))
+		}
+
+		op.StartTime, op.EndTime, err = calendarOf(cals,
+			op.ResourceGroupID).backward(due, time.Duration(op.ProductTime))
+		if err != nil {
+			return nil, err
+		}
+
+		if op.StartTime.Before(start) {
+			return nil, fmt.Errorf("operation %s must start before %s",
+				op.ID, start.Format(time.RFC3339))
+		}
+
+		due = op.StartTime
+		scheduled[i] = op
+	}
+
+	return scheduled, nil
+}
+
+// deadline returns deadline of supply order, it is zero if there is none.
+func deadline(in Input, so entity.SupplyOrder) time.Time {
+	d := so.DeadlineTime
+
+	col, exists := in.Cols[so.ColID]
+	if exists && !col.LatestDesiredDeliveryDate.IsZero() &&
+		(d.IsZero() || col.LatestDesiredDeliveryDate.Before(d)) {
+		d = col.LatestDesiredDeliveryDate
+	}
+
+	return d
+}
+
+// calendars returns calendars of resource groups with fixed operations
+// reserved.
+func calendars(in Input) map[string]*calendar {
+	cals := map[string]*calendar{}
+
+	for _, rg := range in.ResourceGroups {
+		cals[rg.ID] = newCalendar(rg)
+	}
+
+	for _, op := range in.Fixed {
+		if c, exists := cals[op.ResourceGroupID]; exists {
+			c.reserve(op)
+		}
+	}
+
+	return cals
+}
+
+// calendarOf returns calendar of resource group, resource group without
+// known periods has infinite capacity.
+func calendarOf(cals map[string]*calendar, id string) *calendar {
+	c, exists := cals[id]
+	if !exists {
+		c = &calendar{resourceGroupID: id}
+		cals[id] = c
+	}
+	return c
+}
+
+func snapshot(cals map[string]*calendar) map[string][]float64 {
+	s := map[string][]float64{}
+	for id, c := range cals {
+		s[id] = c.snapshot()
+	}
+	return s
+}
+
+func restore(cals map[string]*calendar, s map[string][]float64) {
+	for id, c := range cals {
+		if rs, exists := s[id]; exists {
+			c.restore(rs)
+		}
+	}
+}
+
+// sortOrders returns orders sorted by deadline, orders without deadline
+// are the last, then by id.
+func sortOrders(in Input) []entity.SupplyOrder {
+	sorted := make([]entity.SupplyOrder, len(in.Orders))
+	copy(sorted, in.Orders)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		di, dj := deadline(in, sorted[i]), deadline(in, sorted[j])
+		if !di.Equal(dj) {
+			if di.IsZero() || dj.IsZero() {
+				return dj.IsZero()
+			}
+			return di.Before(dj)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	return sorted
+}
+
+// sortOperations returns operations sorted by sequence number, then by id.
+func sortOperations(
+	ops []entity.SupplyOrderOperation) []entity.SupplyOrderOperation {
+
+	sorted := make([]entity.SupplyOrderOperation, len(ops))
+	copy(sorted, ops)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].SequenceNumber != sorted[j].SequenceNumber {
+			return sorted[i].SequenceNumber < sorted[j].SequenceNumber
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	return sorted
+}
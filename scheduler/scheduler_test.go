@@ -0,0 +1,214 @@
+package scheduler
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+var day1 = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func at(hours int) time.Time {
+	return day1.Add(time.Duration(hours) * time.Hour)
+}
+
+func hours(h int) entity.Duration {
+	return entity.Duration(time.Duration(h) * time.Hour)
+}
+
+func op(id, soID, rgID string, seq, work,
+	space int) entity.SupplyOrderOperation {
+
+	return entity.SupplyOrderOperation{
+		ID:              id,
+		SupplyOrderID:   soID,
+		ResourceGroupID: rgID,
+		SequenceNumber:  seq,
+		ProductTime:     hours(work),
+		SchedulingSpace: hours(space),
+	}
+}
+
+// finite returns resource group with finite capacity which runs one
+// operation at once on the first day and has no capacity after it.
+func finite(id string) entity.ResourceGroup {
+	return entity.ResourceGroup{
+		ID: id,
+		Periods: []entity.ResourceGroupPeriod{
+			{StartDate: day1, AvailableCapacity: hours(24),
+				HasFinateCapacity: true},
+			{StartDate: at(24), HasFinateCapacity: true},
+		},
+	}
+}
+
+func input(orders []entity.SupplyOrder,
+	ops ...entity.SupplyOrderOperation) Input {
+
+	in := Input{
+		Orders:     orders,
+		Operations: map[string][]entity.SupplyOrderOperation{},
+	}
+	for _, o := range ops {
+		in.Operations[o.SupplyOrderID] = append(in.Operations[o.SupplyOrderID],
+			o)
+	}
+	return in
+}
+
+func times(s Schedule) map[string][2]time.Time {
+	ts := map[string][2]time.Time{}
+	for _, o := range s.Operations {
+		ts[o.ID] = [2]time.Time{o.StartTime, o.EndTime}
+	}
+	return ts
+}
+
+func TestForwardSequence(t *testing.T) {
+	in := input([]entity.SupplyOrder{{ID: "SO1"}},
+		op("OP2", "SO1", "RG", 2, 3, 1),
+		op("OP1", "SO1", "RG", 1, 2, 5))
+
+	s := Forward(in, day1)
+
+	want := map[string][2]time.Time{
+		"OP1": {at(0), at(2)},
+		"OP2": {at(3), at(6)},
+	}
+	if got := times(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("times = %v, want %v", got, want)
+	}
+
+	if o := s.Orders[0]; !o.Start.Equal(at(0)) || !o.End.Equal(at(6)) {
+		t.Errorf("order = %+v, want start %v and end %v", o, at(0), at(6))
+	}
+}
+
+func TestForwardExhaustion(t *testing.T) {
+	in := input([]entity.SupplyOrder{
+		{ID: "SO1", DeadlineTime: at(10)},
+		{ID: "SO2", DeadlineTime: at(20)},
+		{ID: "SO3", DeadlineTime: at(30)},
+	},
+		op("OP1", "SO1", "A", 1, 20, 0),
+		op("OP2", "SO2", "A", 1, 2, 0),
+		op("OP3", "SO2", "A", 2, 10, 0),
+		op("OP4", "SO3", "A", 1, 4, 0))
+	in.ResourceGroups = []entity.ResourceGroup{finite("A")}
+
+	s := Forward(in, day1)
+
+	if s.Orders[1].Error == "" {
+		t.Fatalf("SO2 is scheduled, want capacity error")
+	}
+
+	// Capacity taken by OP2 is released, so OP4 fits into capacity left
+	// after OP1.
+	if s.Orders[2].Error != "" {
+		t.Fatalf("SO3 error: %s", s.Orders[2].Error)
+	}
+
+	ts := times(s)
+	for _, id := range []string{"OP2", "OP3"} {
+		if _, scheduled := ts[id]; scheduled {
+			t.Errorf("%s of failed order is scheduled", id)
+		}
+	}
+	if _, scheduled := ts["OP4"]; !scheduled {
+		t.Errorf("OP4 is not scheduled")
+	}
+}
+
+func TestBackwardDeadline(t *testing.T) {
+	in := input([]entity.SupplyOrder{{ID: "SO1", ColID: "COL1",
+		DeadlineTime: at(20)}},
+		op("OP1", "SO1", "A", 1, 2, 0),
+		op("OP2", "SO1", "A", 2, 3, 1))
+	in.ResourceGroups = []entity.ResourceGroup{finite("A")}
+	// COL date is earlier than deadline of supply order, so it is used.
+	in.Cols = map[string]entity.Col{
+		"COL1": {ID: "COL1", LatestDesiredDeliveryDate: at(18)},
+	}
+
+	s := Backward(in, day1)
+
+	want := map[string][2]time.Time{
+		"OP1": {at(12), at(14)},
+		"OP2": {at(15), at(18)},
+	}
+	if got := times(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("times = %v, want %v", got, want)
+	}
+
+	o := s.Orders[0]
+	if !o.End.Equal(o.Deadline) || !o.Deadline.Equal(at(18)) || o.Late {
+		t.Errorf("order = %+v, want end at deadline %v", o, at(18))
+	}
+}
+
+func TestBackwardFallback(t *testing.T) {
+	in := input([]entity.SupplyOrder{{ID: "SO1", DeadlineTime: at(2)}},
+		op("OP1", "SO1", "A", 1, 2, 0),
+		op("OP2", "SO1", "A", 2, 3, 1))
+	in.ResourceGroups = []entity.ResourceGroup{finite("A")}
+
+	s := Backward(in, day1)
+
+	want := map[string][2]time.Time{
+		"OP1": {at(0), at(2)},
+		"OP2": {at(3), at(6)},
+	}
+	if got := times(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("times = %v, want %v", got, want)
+	}
+
+	o := s.Orders[0]
+	if !o.Late || o.Error != "" || !o.End.Equal(at(6)) {
+		t.Errorf("order = %+v, want late with earliest end %v", o, at(6))
+	}
+}
+
+func TestDeterminism(t *testing.T) {
+	orders := []entity.SupplyOrder{
+		{ID: "SO1", DeadlineTime: at(12)},
+		{ID: "SO2", DeadlineTime: at(12)},
+		{ID: "SO3"},
+		{ID: "SO4", DeadlineTime: at(8)},
+	}
+	ops := []entity.SupplyOrderOperation{
+		op("OP1", "SO1", "A", 1, 3, 0),
+		op("OP2", "SO1", "B", 2, 2, 1),
+		op("OP3", "SO2", "A", 1, 4, 0),
+		op("OP4", "SO2", "A", 1, 1, 0),
+		op("OP5", "SO3", "B", 1, 5, 0),
+		op("OP6", "SO4", "A", 1, 6, 0),
+		op("OP7", "SO4", "B", 2, 2, 2),
+	}
+	rgs := []entity.ResourceGroup{finite("A"), finite("B")}
+
+	for _, alg := range []Algorithm{Forward, Backward} {
+		in := input(orders, ops...)
+		in.ResourceGroups = rgs
+		want := alg(in, day1)
+
+		r := rand.New(rand.NewSource(1))
+
+		for i := 0; i < 20; i++ {
+			so := append([]entity.SupplyOrder{}, orders...)
+			r.Shuffle(len(so), func(i, j int) { so[i], so[j] = so[j], so[i] })
+			o := append([]entity.SupplyOrderOperation{}, ops...)
+			r.Shuffle(len(o), func(i, j int) { o[i], o[j] = o[j], o[i] })
+
+			in := input(so, o...)
+			in.ResourceGroups = rgs
+
+			if got := alg(in, day1); !reflect.DeepEqual(got, want) {
+				t.Fatalf("schedule of shuffled input differs:\n%+v\nwant\n%+v",
+					got, want)
+			}
+		}
+	}
+}
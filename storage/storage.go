@@ -0,0 +1,112 @@
+// Package storage describes repositories of planning data. Implementations
+// live in postgres and memory packages.
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dimuls/mipt-hack-accenture/entity"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrBrokenReference is returned if change leaves reference to missing
+	// entity.
+	ErrBrokenReference = errors.New("broken reference")
+)
+
+// Storage groups all repositories so they can be passed around as a whole.
+type Storage struct {
+	Plants                PlantRepo
+	StockingPoints        StockingPointRepo
+	Products              ProductRepo
+	ResourceGroups        ResourceGroupRepo
+	Routings              RoutingRepo
+	Cols                  ColRepo
+	SupplyOrders          SupplyOrderRepo
+	SupplyOrderOperations SupplyOrderOperationRepo
+}
+
+type PlantRepo interface {
+	List() ([]entity.Plant, error)
+	Get(id string) (entity.Plant, error)
+	Add(p entity.Plant) error
+}
+
+type StockingPointRepo interface {
+	List() ([]entity.StockingPoint, error)
+	Get(id string) (entity.StockingPoint, error)
+	Add(sp entity.StockingPoint) error
+}
+
+type ProductRepo interface {
+	List() ([]entity.Product, error)
+	Get(id string) (entity.Product, error)
+	Add(p entity.Product) error
+}
+
+type ResourceGroupRepo interface {
+	// List returns resource groups with their resources.
+	List() ([]entity.ResourceGroup, error)
+	// ListWithPeriods returns resource groups with their resources and
+	// periods.
+	ListWithPeriods() ([]entity.ResourceGroup, error)
+	// ByPlant returns resource groups of plant with their resources.
+	ByPlant(plantID string) ([]entity.ResourceGroup, error)
+	// Get returns resource group with its resources and periods.
+	Get(id string) (entity.ResourceGroup, error)
+	// Add adds resource group with its resources and periods.
+	Add(rg entity.ResourceGroup) error
+	// Update updates resource group and replaces its resources and periods.
+	Update(rg entity.ResourceGroup) error
+	// Remove removes resource group with its resources and periods.
+	Remove(id string) error
+
+	AddResource(r entity.Resource) error
+	AddPeriod(p entity.ResourceGroupPeriod) error
+}
+
+type RoutingRepo interface {
+	List() ([]entity.Routing, error)
+	Get(id string) (entity.Routing, error)
+	Add(r entity.Routing) error
+
+	// Steps returns routing steps ordered by sequence number.
+	Steps(routingID string) ([]entity.RoutingStep, error)
+	// AllSteps returns steps of all routings ordered by routing id and
+	// sequence number.
+	AllSteps() ([]entity.RoutingStep, error)
+	AddStep(rs entity.RoutingStep) error
+}
+
+type ColRepo interface {
+	List() ([]entity.Col, error)
+	Get(id string) (entity.Col, error)
+	Add(c entity.Col) error
+}
+
+type SupplyOrderRepo interface {
+	List() ([]entity.SupplyOrder, error)
+	Get(id string) (entity.SupplyOrder, error)
+	// ByCol returns supply orders of COL ordered by order position.
+	ByCol(colID string) ([]entity.SupplyOrder, error)
+	Add(so entity.SupplyOrder) error
+}
+
+type SupplyOrderOperationRepo interface {
+	Get(id string) (entity.SupplyOrderOperation, error)
+	// BySupplyOrder returns operations of supply order ordered by sequence
+	// number.
+	BySupplyOrder(supplyOrderID string) ([]entity.SupplyOrderOperation, error)
+	// ByResourceGroup returns operations of resource group which overlap
+	// [from, to) ordered by start time.
+	ByResourceGroup(resourceGroupID string, from, to time.Time) (
+		[]entity.SupplyOrderOperation, error)
+	Add(op entity.SupplyOrderOperation) error
+	// SetTimes updates start and end times of operations all at once. Supply
+	// orders of operations are updated too, they start with their first
+	// operation and end with their last one.
+	SetTimes(ops []entity.SupplyOrderOperation) error
+}